@@ -0,0 +1,253 @@
+package rbtree
+
+import (
+	"cmp"
+	"iter"
+
+	"golang.org/x/exp/constraints"
+)
+
+// CombineFunc returns aggregate of a subtree by aggregates of its left
+// and right subtrees and by the key and the value of its root. It must be
+// associative, that is the aggregate must depend on the keys and values
+// in order of keys only, not on shape of the tree.
+type CombineFunc[Key, Value, Agg any] func(left Agg, key Key, value Value,
+	right Agg) Agg
+
+// augmentedValue is value of a node of the AugmentedTree
+type augmentedValue[Value, Agg any] struct {
+	agg   Agg // aggregate of subtree of the node
+	value Value
+}
+
+// AugmentedTree is a Tree, every node of which keeps aggregate of its
+// subtree, such as sum of values, minimal value or count. The aggregates
+// are updated by rotations and on paths of insertions and deletions, and
+// the Aggregate returns aggregate of any range of keys in O(logn).
+//
+//	var tr = rbtree.NewAugmented[int, int](
+//	    func(left int, _ int, value int, right int) int {
+//	        return left + value + right // sum of values
+//	    }, 0)
+type AugmentedTree[Key, Value, Agg any] struct {
+	tree     *Tree[Key, augmentedValue[Value, Agg]]
+	combine  CombineFunc[Key, Value, Agg]
+	identity Agg
+}
+
+// NewAugmented creates the new empty AugmentedTree. The identity is
+// aggregate of an empty tree.
+func NewAugmented[Key constraints.Ordered, Value, Agg any](
+	combine CombineFunc[Key, Value, Agg],
+	identity Agg) (at *AugmentedTree[Key, Value, Agg]) {
+
+	at = NewAugmentedFunc(cmp.Compare[Key], combine, identity)
+	at.tree.ordered = newOrdered[Key, augmentedValue[Value, Agg]]()
+	return
+}
+
+// NewAugmentedFunc is like the NewAugmented, but the tree uses given
+// function to compare keys. See also NewFunc.
+func NewAugmentedFunc[Key, Value, Agg any](cmp CompareFunc[Key],
+	combine CombineFunc[Key, Value, Agg],
+	identity Agg) (at *AugmentedTree[Key, Value, Agg]) {
+
+	at = &AugmentedTree[Key, Value, Agg]{
+		tree:     NewFunc[Key, augmentedValue[Value, Agg]](cmp),
+		combine:  combine,
+		identity: identity,
+	}
+	at.tree.augment = at.augment
+	return
+}
+
+// agg of subtree of the n
+func (at *AugmentedTree[Key, Value, Agg]) agg(
+	n *node[Key, augmentedValue[Value, Agg]]) Agg {
+
+	if n == at.tree.sentinel {
+		return at.identity
+	}
+	return n.value.agg
+}
+
+// augment updates aggregate of the n
+func (at *AugmentedTree[Key, Value, Agg]) augment(
+	n *node[Key, augmentedValue[Value, Agg]]) {
+
+	n.value.agg = at.combine(at.agg(n.left), n.key, n.value.value,
+		at.agg(n.right))
+}
+
+// Set the value. O(logn). This will overwrite the existing value.
+func (at *AugmentedTree[Key, Value, Agg]) Set(key Key,
+	value Value) (added bool) {
+
+	return at.tree.Set(key, augmentedValue[Value, Agg]{value: value})
+}
+
+// SetNx doesn't overwrites an existing value.
+func (at *AugmentedTree[Key, Value, Agg]) SetNx(key Key,
+	value Value) (added bool) {
+
+	return at.tree.SetNx(key, augmentedValue[Value, Agg]{value: value})
+}
+
+// Del deletes value by key. O(logn). It returns false,
+// if key doesn't exits.
+func (at *AugmentedTree[Key, Value, Agg]) Del(key Key) (deleted bool) {
+	return at.tree.Del(key)
+}
+
+// Get O(logn). It returns zero value, if key doesn't exist.
+func (at *AugmentedTree[Key, Value, Agg]) Get(key Key) Value {
+	return at.tree.Get(key).value
+}
+
+// GetEx O(logn). It returns false, if key doesn't exist.
+func (at *AugmentedTree[Key, Value, Agg]) GetEx(key Key) (Value, bool) {
+	var av, ok = at.tree.GetEx(key)
+	return av.value, ok
+}
+
+// IsExist O(logn)
+func (at *AugmentedTree[Key, Value, Agg]) IsExist(key Key) bool {
+	return at.tree.IsExist(key)
+}
+
+// Len O(1)
+func (at *AugmentedTree[Key, Value, Agg]) Len() int {
+	return at.tree.Len()
+}
+
+// Empty makes the tree empty O(1).
+func (at *AugmentedTree[Key, Value, Agg]) Empty() {
+	at.tree.Empty()
+}
+
+// Clone returns an independent copy of the tree O(1), see Tree.Clone.
+func (at *AugmentedTree[Key, Value, Agg]) Clone() (
+	c *AugmentedTree[Key, Value, Agg]) {
+
+	c = &AugmentedTree[Key, Value, Agg]{
+		tree:     at.tree.Clone(),
+		combine:  at.combine,
+		identity: at.identity,
+	}
+	c.tree.augment = c.augment
+	return
+}
+
+// Max returns maximum index and its value O(1)
+func (at *AugmentedTree[Key, Value, Agg]) Max() (Key, Value) {
+	var key, av = at.tree.Max()
+	return key, av.value
+}
+
+// Min returns minimum indexed and its value O(1)
+func (at *AugmentedTree[Key, Value, Agg]) Min() (Key, Value) {
+	var key, av = at.tree.Min()
+	return key, av.value
+}
+
+// Walk on the tree like the Tree.Walk does.
+func (at *AugmentedTree[Key, Value, Agg]) Walk(from, to Key,
+	walkFunc WalkFunc[Key, Value]) error {
+
+	return at.tree.Walk(from, to,
+		func(key Key, av augmentedValue[Value, Agg]) error {
+			return walkFunc(key, av.value)
+		})
+}
+
+// values of the seq
+func (at *AugmentedTree[Key, Value, Agg]) values(
+	seq iter.Seq2[Key, augmentedValue[Value, Agg]]) iter.Seq2[Key, Value] {
+
+	return func(yield func(Key, Value) bool) {
+		for key, av := range seq {
+			if !yield(key, av.value) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over all key-value pairs of the tree in
+// ascending order of keys. See Tree.All for details.
+func (at *AugmentedTree[Key, Value, Agg]) All() iter.Seq2[Key, Value] {
+	return at.values(at.tree.All())
+}
+
+// Range returns an iterator over key-value pairs at given range. See
+// Tree.Range for details.
+func (at *AugmentedTree[Key, Value, Agg]) Range(from,
+	to Key) iter.Seq2[Key, Value] {
+
+	return at.values(at.tree.Range(from, to))
+}
+
+// Total returns aggregate of all keys O(1).
+func (at *AugmentedTree[Key, Value, Agg]) Total() Agg {
+	return at.agg(at.tree.root)
+}
+
+// Aggregate returns aggregate of keys at given range O(logn). The range
+// is inclusive, and the from can be greater than the to, the same way as
+// for the Walk, but keys are aggregated in ascending order anyway. It
+// returns the identity, if there are no keys at the range.
+func (at *AugmentedTree[Key, Value, Agg]) Aggregate(from, to Key) Agg {
+
+	var t = at.tree
+	if t.cmp(from, to) > 0 {
+		from, to = to, from
+	}
+
+	// the highest node of the range, other nodes are in its subtree
+	var n = t.root
+	for n != t.sentinel {
+		if t.cmp(n.key, from) < 0 {
+			n = n.right
+		} else if t.cmp(n.key, to) > 0 {
+			n = n.left
+		} else {
+			break
+		}
+	}
+	if n == t.sentinel {
+		return at.identity
+	}
+
+	return at.combine(at.aggFrom(n.left, from), n.key, n.value.value,
+		at.aggTo(n.right, to))
+}
+
+// aggFrom returns aggregate of keys of subtree of the n greater than or
+// equal to the from
+func (at *AugmentedTree[Key, Value, Agg]) aggFrom(
+	n *node[Key, augmentedValue[Value, Agg]], from Key) Agg {
+
+	for n != at.tree.sentinel && at.tree.cmp(n.key, from) < 0 {
+		n = n.right
+	}
+	if n == at.tree.sentinel {
+		return at.identity
+	}
+	return at.combine(at.aggFrom(n.left, from), n.key, n.value.value,
+		at.agg(n.right))
+}
+
+// aggTo returns aggregate of keys of subtree of the n less than or
+// equal to the to
+func (at *AugmentedTree[Key, Value, Agg]) aggTo(
+	n *node[Key, augmentedValue[Value, Agg]], to Key) Agg {
+
+	for n != at.tree.sentinel && at.tree.cmp(n.key, to) > 0 {
+		n = n.left
+	}
+	if n == at.tree.sentinel {
+		return at.identity
+	}
+	return at.combine(at.agg(n.left), n.key, n.value.value,
+		at.aggTo(n.right, to))
+}
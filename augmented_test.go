@@ -0,0 +1,140 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sumCombine(left int, _ int, value int, right int) int {
+	return left + value + right
+}
+
+func TestAugmentedTree_Aggregate(t *testing.T) {
+
+	var at = NewAugmented(sumCombine, 0)
+	assert.Zero(t, at.Total())
+	assert.Zero(t, at.Aggregate(0, 100))
+
+	for i := 1; i <= 10; i++ {
+		assert.True(t, at.Set(i*10, i))
+	}
+	checkTree(t, at.tree)
+
+	assert.Equal(t, 55, at.Total())
+	assert.Equal(t, 55, at.Aggregate(0, 1000))
+	assert.Equal(t, 2+3+4, at.Aggregate(20, 40))
+	assert.Equal(t, 2+3+4, at.Aggregate(40, 20))
+	assert.Equal(t, 2+3+4, at.Aggregate(11, 49))
+	assert.Equal(t, 5, at.Aggregate(50, 50))
+	assert.Zero(t, at.Aggregate(51, 59))
+	assert.Zero(t, at.Aggregate(101, 200))
+
+	assert.False(t, at.Set(50, 50))
+	assert.False(t, at.SetNx(50, 0))
+	assert.Equal(t, 50, at.Get(50))
+	assert.Equal(t, 100, at.Total())
+	checkTree(t, at.tree)
+
+	assert.True(t, at.Del(50))
+	assert.False(t, at.Del(50))
+	assert.Equal(t, 50, at.Total())
+	checkTree(t, at.tree)
+
+	var c = at.Clone()
+	c.Set(1, 1000)
+	assert.Equal(t, 1050, c.Total())
+	assert.Equal(t, 50, at.Total())
+	checkTree(t, c.tree)
+
+	at.Empty()
+	assert.Zero(t, at.Total())
+	assert.Zero(t, at.Len())
+}
+
+func TestAugmentedTree_order(t *testing.T) {
+
+	// not commutative
+	var at = NewAugmented(func(left string, key int, _ struct{},
+		right string) string {
+
+		return left + strconv.Itoa(key) + right
+	}, "")
+
+	for _, key := range rand.New(rand.NewSource(1)).Perm(10) {
+		at.Set(key, struct{}{})
+	}
+	assert.Equal(t, "0123456789", at.Total())
+	assert.Equal(t, "234567", at.Aggregate(7, 2))
+}
+
+func TestAugmentedTree_random(t *testing.T) {
+
+	var (
+		at     = NewAugmented(sumCombine, 0)
+		oracle = make(map[int]int)
+		rnd    = rand.New(rand.NewSource(1))
+	)
+
+	for i := 0; i < 3000; i++ {
+		var key = rnd.Intn(1000)
+		switch rnd.Intn(4) {
+		case 0:
+			var to, sum = rnd.Intn(1000), 0
+			for k, v := range oracle {
+				if min(key, to) <= k && k <= max(key, to) {
+					sum += v
+				}
+			}
+			assert.Equal(t, sum, at.Aggregate(key, to))
+		case 1:
+			at.Del(key)
+			delete(oracle, key)
+		default:
+			at.Set(key, i)
+			oracle[key] = i
+		}
+		if i%100 == 0 {
+			checkTree(t, at.tree)
+		}
+	}
+	checkTree(t, at.tree)
+	assert.Equal(t, len(oracle), at.Len())
+}
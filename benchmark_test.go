@@ -42,11 +42,13 @@ import (
 )
 
 var (
+	globalTree      *Tree[int, string]
 	globalString    string
 	globalBool      bool
 	globalErr       error
 	globalSlice     []string
 	globalSliceKeys []int
+	globalInt       int
 )
 
 func init() {
@@ -60,6 +62,7 @@ func Benchmark(b *testing.B) {
 	}{
 		{"tree", New[int, string]()},
 		{"thread-safe", NewThreadSafe[int, string]()},
+		{"concurrent", NewConcurrent[int, string]()},
 	} {
 		b.Run(nt.name, func(b *testing.B) {
 			b.Run("sequential", func(b *testing.B) {
@@ -114,6 +117,109 @@ func sequentialSetNx(tr TreeInterface[int, string]) func(b *testing.B) {
 	}
 }
 
+// BenchmarkParallel compares the thread-safe trees under mixed parallel
+// load: 90% of Get, 9% of Set and 1% of Walk over 1000 keys
+func BenchmarkParallel(b *testing.B) {
+	b.Run("thread-safe", parallelMixed(NewThreadSafe[int, string]()))
+	b.Run("concurrent", parallelMixed(NewConcurrent[int, string]()))
+	b.Run("sharded", parallelMixed(NewSharded[int, string](
+		12500, 25000, 37500, 50000, 62500, 75000, 87500)))
+}
+
+func parallelMixed(tr TreeInterface[int, string]) func(b *testing.B) {
+	return func(b *testing.B) {
+		const n = 100000
+		b.StopTimer()
+		tr.Empty()
+		for i := 0; i < n; i++ {
+			tr.Set(i, "")
+		}
+		var walkFunc = func(int, string) error { return nil }
+		b.StartTimer()
+		b.RunParallel(func(pb *testing.PB) {
+			var rnd = rand.New(rand.NewSource(rand.Int63()))
+			for pb.Next() {
+				var key = rnd.Intn(n)
+				switch op := rnd.Intn(100); {
+				case op < 90:
+					tr.Get(key)
+				case op < 99:
+					tr.Set(key, "")
+				default:
+					tr.Walk(key, key+1000, walkFunc)
+				}
+			}
+		})
+		b.ReportAllocs()
+	}
+}
+
+// BenchmarkFromSorted compares n calls of Set with the FromSorted
+func BenchmarkFromSorted(b *testing.B) {
+	b.Run("set", sequentialSet(New[int, string]()))
+	b.Run("from-sorted", sequentialFromSorted)
+}
+
+// BenchmarkSumRange compares sum of values of a range by the Walk with
+// the SumRange
+func BenchmarkSumRange(b *testing.B) {
+	const n = 100000
+
+	var nt = NewNumeric[int, int]()
+	for i := 0; i < n; i++ {
+		nt.Set(int(rand.Int63n(math.MaxInt)), i)
+	}
+	b.Run("walk", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var from = int(rand.Int63n(math.MaxInt))
+			globalInt = 0
+			nt.Walk(from, from/2+math.MaxInt/2, func(_, value int) error {
+				globalInt += value
+				return nil
+			})
+		}
+		b.ReportAllocs()
+	})
+	b.Run("sum-range", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			var from = int(rand.Int63n(math.MaxInt))
+			globalInt = nt.SumRange(from, from/2+math.MaxInt/2)
+		}
+		b.ReportAllocs()
+	})
+}
+
+// BenchmarkClone measures a clone of a big tree with the first change of
+// the clone, that copies nodes of a path only
+func BenchmarkClone(b *testing.B) {
+	const n = 1000000
+
+	var tr = New[int, int]()
+	for i := 0; i < n; i++ {
+		tr.Set(i, i)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var c = tr.Clone()
+		c.Set(int(rand.Int63n(n)), i)
+	}
+	b.ReportAllocs()
+}
+
+func sequentialFromSorted(b *testing.B) {
+	b.StopTimer()
+	var (
+		keys   = make([]int, b.N)
+		values = make([]string, b.N)
+	)
+	for i := range keys {
+		keys[i] = i
+	}
+	b.StartTimer()
+	globalTree, globalErr = FromSorted(keys, values)
+	b.ReportAllocs()
+}
+
 func sequentialGet(tr TreeInterface[int, string]) func(b *testing.B) {
 	return func(b *testing.B) {
 		b.StopTimer()
@@ -0,0 +1,262 @@
+package rbtree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"math"
+)
+
+// Binary format of a Tree, all integers are big-endian.
+//
+//	magic    [3]byte "rbt"
+//	version  uint8   1
+//	count    uint64  number of elements
+//	elements [count]{
+//	    keyLength   uint32
+//	    key         [keyLength]byte
+//	    valueLength uint32
+//	    value       [valueLength]byte
+//	}
+//	checksum uint32  CRC-32 (IEEE) of all previous bytes
+//
+// The elements are in ascending order of keys.
+const (
+	binaryMagic      = "rbt"
+	binaryVersion    = 1
+	binaryHeaderSize = len(binaryMagic) + 1 + 8
+	binaryFlushSize  = 64 * 1024
+)
+
+var (
+	// ErrInvalidData is returned when decoding malformed binary data.
+	ErrInvalidData = errors.New("invalid binary data")
+	// ErrChecksum is returned when checksum of binary data doesn't match.
+	ErrChecksum = errors.New("checksum mismatch")
+)
+
+// Encode writes the Tree to the w using given codecs of keys and values.
+// It returns number of bytes written. See also WriteTo.
+func (t *Tree[Key, Value]) Encode(w io.Writer, keyCodec Codec[Key],
+	valueCodec Codec[Value]) (n int64, err error) {
+
+	var (
+		hash = crc32.NewIEEE()
+		buf  = make([]byte, 0, binaryHeaderSize)
+
+		flush = func() (err error) {
+			hash.Write(buf)
+			var m int
+			m, err = w.Write(buf)
+			n += int64(m)
+			buf = buf[:0]
+			return
+		}
+	)
+
+	buf = append(buf, binaryMagic...)
+	buf = append(buf, binaryVersion)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(t.len))
+
+	// the min and the sentinel of a zero Tree are nil, that is it's empty
+	for x := t.min; x != t.sentinel; x = t.successor(x) {
+		if buf, err = appendRecord(buf, keyCodec, x.key); err != nil {
+			return
+		}
+		if buf, err = appendRecord(buf, valueCodec, x.value); err != nil {
+			return
+		}
+		if len(buf) >= binaryFlushSize {
+			if err = flush(); err != nil {
+				return
+			}
+		}
+	}
+
+	hash.Write(buf)
+	buf = binary.BigEndian.AppendUint32(buf, hash.Sum32())
+	var m int
+	m, err = w.Write(buf)
+	n += int64(m)
+	return
+}
+
+// appendRecord appends length prefixed encoded value
+func appendRecord[T any](buf []byte, codec Codec[T], v T) (_ []byte,
+	err error) {
+
+	var start = len(buf)
+	buf = append(buf, 0, 0, 0, 0) // length placeholder
+	if buf, err = codec.Encode(buf, v); err != nil {
+		return
+	}
+	var length = len(buf) - start - 4
+	if uint64(length) > math.MaxUint32 {
+		return nil, errors.New("encoded key or value is too long")
+	}
+	binary.BigEndian.PutUint32(buf[start:], uint32(length))
+	return buf, nil
+}
+
+// Decode replaces content of the Tree with data read from the r using
+// given codecs of keys and values. It returns number of bytes read. It
+// reads exactly the encoded Tree, nothing more. The Tree is built in O(n)
+// and it is not changed on error. See also ReadFrom.
+func (t *Tree[Key, Value]) Decode(r io.Reader, keyCodec Codec[Key],
+	valueCodec Codec[Value]) (n int64, err error) {
+
+	if err = t.lazyInit(); err != nil {
+		return
+	}
+
+	var (
+		hash = crc32.NewIEEE()
+		buf  = make([]byte, binaryHeaderSize)
+
+		large bytes.Buffer
+
+		read = func(size int) (err error) {
+			if size > binaryFlushSize && size > cap(buf) {
+				// grow progressively, the size can be corrupted
+				large.Reset()
+				var m int64
+				m, err = io.CopyN(&large, r, int64(size))
+				n += m
+				buf = large.Bytes()
+			} else {
+				if cap(buf) < size {
+					buf = make([]byte, size)
+				}
+				buf = buf[:size]
+				var m int
+				m, err = io.ReadFull(r, buf)
+				n += int64(m)
+				buf = buf[:m]
+			}
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			hash.Write(buf)
+			return
+		}
+
+		readRecord = func() (data []byte, err error) {
+			if err = read(4); err != nil {
+				return
+			}
+			if err = read(int(binary.BigEndian.Uint32(buf))); err != nil {
+				return
+			}
+			return buf, nil
+		}
+	)
+
+	if err = read(binaryHeaderSize); err != nil {
+		return
+	}
+	if string(buf[:len(binaryMagic)]) != binaryMagic {
+		return n, invalidDataf("bad magic")
+	}
+	if version := buf[len(binaryMagic)]; version != binaryVersion {
+		return n, invalidDataf("unsupported version %d", version)
+	}
+	var count = binary.BigEndian.Uint64(buf[len(binaryMagic)+1:])
+	if count > math.MaxInt {
+		return n, invalidDataf("bad count %d", count)
+	}
+
+	var root *node[Key, Value]
+	root, err = t.build(int(count), func() (key Key, value Value, err error) {
+		var data []byte
+		if data, err = readRecord(); err != nil {
+			return
+		}
+		if key, err = keyCodec.Decode(data); err != nil {
+			return
+		}
+		if data, err = readRecord(); err != nil {
+			return
+		}
+		value, err = valueCodec.Decode(data)
+		return
+	})
+	if err != nil {
+		return
+	}
+
+	var sum = hash.Sum32()
+	if err = read(4); err != nil {
+		return
+	}
+	if binary.BigEndian.Uint32(buf) != sum {
+		return n, ErrChecksum
+	}
+
+	t.replace(root, int(count))
+	return
+}
+
+func defaultCodecs[Key, Value any]() (keyCodec Codec[Key],
+	valueCodec Codec[Value], err error) {
+
+	var okKey, okValue bool
+	keyCodec, okKey = DefaultCodec[Key]()
+	valueCodec, okValue = DefaultCodec[Value]()
+	if !okKey || !okValue {
+		err = ErrNoCodec
+	}
+	return
+}
+
+// WriteTo writes the Tree to the w using default codecs. It implements
+// io.WriterTo interface. See DefaultCodec and Encode for details.
+func (t *Tree[Key, Value]) WriteTo(w io.Writer) (n int64, err error) {
+	var keyCodec, valueCodec, errCodec = defaultCodecs[Key, Value]()
+	if errCodec != nil {
+		return 0, errCodec
+	}
+	return t.Encode(w, keyCodec, valueCodec)
+}
+
+// ReadFrom replaces content of the Tree with data read from the r using
+// default codecs. It implements io.ReaderFrom interface. See DefaultCodec
+// and Decode for details.
+func (t *Tree[Key, Value]) ReadFrom(r io.Reader) (n int64, err error) {
+	var keyCodec, valueCodec, errCodec = defaultCodecs[Key, Value]()
+	if errCodec != nil {
+		return 0, errCodec
+	}
+	return t.Decode(r, keyCodec, valueCodec)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+// It uses default codecs, see DefaultCodec.
+func (t *Tree[Key, Value]) MarshalBinary() (data []byte, err error) {
+	var buf bytes.Buffer
+	if _, err = t.WriteTo(&buf); err != nil {
+		return
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+// It uses default codecs, see DefaultCodec.
+func (t *Tree[Key, Value]) UnmarshalBinary(data []byte) (err error) {
+	if err = t.lazyInit(); err != nil {
+		return
+	}
+	var (
+		r   = bytes.NewReader(data)
+		tmp = t.newTree()
+	)
+	if _, err = tmp.ReadFrom(r); err != nil {
+		return
+	}
+	if r.Len() != 0 {
+		return invalidDataf("trailing data")
+	}
+	t.replace(tmp.root, tmp.len)
+	return
+}
@@ -0,0 +1,298 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"bytes"
+	"encoding"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ encoding.BinaryMarshaler   = (*Tree[int, string])(nil)
+	_ encoding.BinaryUnmarshaler = (*Tree[int, string])(nil)
+	_ io.WriterTo                = (*Tree[int, string])(nil)
+	_ io.ReaderFrom              = (*Tree[int, string])(nil)
+	_ encoding.BinaryMarshaler   = (*TreeThreadSafe[int, string])(nil)
+	_ encoding.BinaryUnmarshaler = (*TreeThreadSafe[int, string])(nil)
+)
+
+func TestTree_MarshalBinary(t *testing.T) {
+
+	var tr = New[int, string]()
+	for i := 0; i < Count; i++ {
+		var k = rand.Intn(Count * 10)
+		tr.Set(k, strconv.Itoa(k))
+	}
+
+	var data, err = tr.MarshalBinary()
+	assert.NoError(t, err)
+
+	var got = New[int, string]()
+	got.Set(-1, "must be removed")
+	assert.NoError(t, got.UnmarshalBinary(data))
+	checkTree(t, got)
+	assert.Equal(t, tr.SliceKeys(math.MinInt, math.MaxInt),
+		got.SliceKeys(math.MinInt, math.MaxInt))
+	assert.Equal(t, tr.Slice(math.MinInt, math.MaxInt),
+		got.Slice(math.MinInt, math.MaxInt))
+
+	// zero Tree
+	var zero Tree[int, string]
+	assert.NoError(t, zero.UnmarshalBinary(data))
+	assert.Equal(t, tr.Len(), zero.Len())
+	zero.Set(-1, "ok")
+	checkTree(t, &zero)
+
+	// empty
+	data, err = New[int, string]().MarshalBinary()
+	assert.NoError(t, err)
+	assert.NoError(t, got.UnmarshalBinary(data))
+	assert.Zero(t, got.Len())
+	checkTree(t, got)
+}
+
+func TestTree_WriteTo(t *testing.T) {
+
+	var (
+		tr  = NewFunc[time.Time, []byte](time.Time.Compare)
+		now = time.Date(2022, 8, 28, 18, 54, 57, 0, time.UTC)
+	)
+	for i := 0; i < 100; i++ {
+		tr.Set(now.Add(time.Duration(i)*time.Hour), []byte(strconv.Itoa(i)))
+	}
+
+	var buf bytes.Buffer
+	var n, err = tr.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(buf.Len()), n)
+
+	// the stream contains something after the tree
+	buf.WriteString("tail")
+
+	var got = NewFunc[time.Time, []byte](time.Time.Compare)
+	var m int64
+	m, err = got.ReadFrom(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, n, m)
+	assert.Equal(t, "tail", buf.String())
+	assert.Equal(t, 100, got.Len())
+	assert.Equal(t, []byte("42"), got.Get(now.Add(42*time.Hour)))
+	checkTree(t, got)
+}
+
+type upperCodec struct{}
+
+func (upperCodec) Encode(b []byte, v string) ([]byte, error) {
+	if v == "" {
+		return nil, errors.New("empty")
+	}
+	return append(b, bytes.ToUpper([]byte(v))...), nil
+}
+
+func (upperCodec) Decode(data []byte) (string, error) {
+	return string(bytes.ToLower(data)), nil
+}
+
+func TestTree_Encode(t *testing.T) {
+
+	var tr = New[string, string]()
+	tr.Set("a", "x")
+	tr.Set("b", "y")
+
+	var buf bytes.Buffer
+	var _, err = tr.Encode(&buf, upperCodec{}, upperCodec{})
+	assert.NoError(t, err)
+	assert.Contains(t, buf.String(), "Y")
+
+	var got = New[string, string]()
+	_, err = got.Decode(&buf, upperCodec{}, upperCodec{})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"x", "y"}, got.Slice("a", "b"))
+
+	tr.Set("c", "")
+	_, err = tr.Encode(&buf, upperCodec{}, upperCodec{})
+	assert.Error(t, err)
+
+	// no default codec
+	var st = New[int, struct{}]()
+	_, err = st.WriteTo(&buf)
+	assert.ErrorIs(t, err, ErrNoCodec)
+	_, err = st.ReadFrom(&buf)
+	assert.ErrorIs(t, err, ErrNoCodec)
+	_, err = st.MarshalBinary()
+	assert.ErrorIs(t, err, ErrNoCodec)
+}
+
+func TestTree_MarshalBinary_zero(t *testing.T) {
+
+	var (
+		zero      Tree[int, string]
+		data, err = zero.MarshalBinary()
+		empty, _  = New[int, string]().MarshalBinary()
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, empty, data)
+
+	var got = New[int, string]()
+	got.Set(1, "one")
+	assert.NoError(t, got.UnmarshalBinary(data))
+	assert.Zero(t, got.Len())
+	checkTree(t, got)
+
+	var buf bytes.Buffer
+	_, err = zero.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, empty, buf.Bytes())
+
+	// thread-safe
+	var zts TreeThreadSafe[int, string]
+	data, err = zts.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Equal(t, empty, data)
+
+	buf.Reset()
+	_, err = zts.WriteTo(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, empty, buf.Bytes())
+
+	buf.Reset()
+	var codec, _ = DefaultCodec[int]()
+	_, err = zts.Encode(&buf, codec, upperCodec{})
+	assert.NoError(t, err)
+	assert.Equal(t, empty, buf.Bytes())
+
+	var rt TreeThreadSafe[int, string]
+	assert.NoError(t, rt.UnmarshalBinary(data))
+	assert.Zero(t, rt.Len())
+}
+
+func TestTree_UnmarshalBinary_invalid(t *testing.T) {
+
+	var tr = New[int, string]()
+	tr.Set(1, "one")
+	tr.Set(2, "two")
+
+	var data, _ = tr.MarshalBinary()
+
+	var corrupt = func(f func(data []byte) []byte) []byte {
+		return f(append([]byte{}, data...))
+	}
+
+	var got = New[int, string]()
+	got.Set(10, "ten")
+
+	for _, tt := range []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{"empty", nil, io.ErrUnexpectedEOF},
+		{"truncated", data[:len(data)-1], io.ErrUnexpectedEOF},
+		{"magic", corrupt(func(d []byte) []byte {
+			d[0] = 'x'
+			return d
+		}), ErrInvalidData},
+		{"version", corrupt(func(d []byte) []byte {
+			d[3] = 2
+			return d
+		}), ErrInvalidData},
+		{"count", corrupt(func(d []byte) []byte {
+			d[4] = 0xff
+			return d
+		}), ErrInvalidData},
+		{"checksum", corrupt(func(d []byte) []byte {
+			d[len(d)-1]++
+			return d
+		}), ErrChecksum},
+		{"trailing", append(append([]byte{}, data...), 0), ErrInvalidData},
+	} {
+		assert.ErrorIs(t, got.UnmarshalBinary(tt.data), tt.err, tt.name)
+	}
+
+	// not sorted
+	var buf bytes.Buffer
+	var rev = NewFunc[int, string](func(a, b int) int { return b - a })
+	rev.Set(1, "one")
+	rev.Set(2, "two")
+	rev.WriteTo(&buf)
+	assert.ErrorIs(t, got.UnmarshalBinary(buf.Bytes()), ErrNotSorted)
+
+	// not changed
+	assert.Equal(t, []int{10}, got.SliceKeys(math.MinInt, math.MaxInt))
+
+	// key type is not ordered
+	var bt Tree[[]byte, string]
+	assert.ErrorIs(t, bt.UnmarshalBinary(data), ErrNotOrdered)
+}
+
+func TestTreeThreadSafe_MarshalBinary(t *testing.T) {
+
+	var tr = NewThreadSafe[int, string]()
+	tr.Set(1, "one")
+	tr.Set(2, "two")
+
+	var data, err = tr.MarshalBinary()
+	assert.NoError(t, err)
+
+	var got TreeThreadSafe[int, string]
+	assert.NoError(t, got.UnmarshalBinary(data))
+	assert.Equal(t, []string{"one", "two"}, got.Slice(0, 10))
+
+	var buf bytes.Buffer
+	_, err = tr.WriteTo(&buf)
+	assert.NoError(t, err)
+	var rf TreeThreadSafe[int, string]
+	_, err = rf.ReadFrom(&buf)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, rf.Len())
+
+	var codec, _ = DefaultCodec[int]()
+	_, err = tr.Encode(&buf, codec, upperCodec{})
+	assert.NoError(t, err)
+	var dc TreeThreadSafe[int, string]
+	_, err = dc.Decode(&buf, codec, upperCodec{})
+	assert.NoError(t, err)
+	assert.Equal(t, "two", dc.Get(2))
+}
@@ -0,0 +1,279 @@
+package rbtree
+
+import (
+	"cmp"
+	"errors"
+	"iter"
+	"math/bits"
+	"reflect"
+
+	"golang.org/x/exp/constraints"
+)
+
+// ErrNotSorted is returned when a Tree built from unsorted input.
+var ErrNotSorted = errors.New("keys are not sorted in ascending order")
+
+// ErrLengthMismatch is returned when keys and values have different length.
+var ErrLengthMismatch = errors.New("keys and values have different length")
+
+// ErrNotOrdered is returned when a zero Tree can't be initialized
+// because its key type is not ordered. Use NewFunc instead.
+var ErrNotOrdered = errors.New("key type of zero Tree is not ordered")
+
+// build returns root of a tree of n elements, returned by the next
+// function in ascending order of keys. It builds perfectly balanced tree
+// in O(n), the nodes of the deepest level are red, all other are black.
+// The Tree is not changed, use the replace to set the root.
+func (t *Tree[Key, Value]) build(n int,
+	next func() (Key, Value, error)) (root *node[Key, Value], err error) {
+
+	var (
+		deepest = bits.Len(uint(n)) - 1
+		prev    *node[Key, Value]
+	)
+
+	var build func(size, depth int, parent *node[Key, Value]) (
+		x *node[Key, Value], err error)
+
+	build = func(size, depth int, parent *node[Key, Value]) (
+		x *node[Key, Value], err error) {
+
+		if size == 0 {
+			return t.sentinel, nil
+		}
+
+		x = &node[Key, Value]{
+			parent: parent,
+			size:   size,
+			gen:    t.gen.Load(),
+			color:  black,
+		}
+		if depth == deepest && depth > 0 {
+			x.color = red
+		}
+
+		var left = (size - 1) / 2
+		if x.left, err = build(left, depth+1, x); err != nil {
+			return
+		}
+		if x.key, x.value, err = next(); err != nil {
+			return
+		}
+		if prev != nil && t.cmp(prev.key, x.key) >= 0 {
+			return nil, ErrNotSorted
+		}
+		prev = x
+		if x.right, err = build(size-1-left, depth+1, x); err == nil &&
+			t.augment != nil {
+			t.augment(x)
+		}
+		return
+	}
+
+	return build(n, 0, nil)
+}
+
+// replace content of the Tree with given one
+func (t *Tree[Key, Value]) replace(root *node[Key, Value], n int) {
+	t.recordRoot()
+	t.reset(root, n)
+	t.stale = false
+}
+
+// buildSlices replaces content of the Tree with given sorted keys and
+// values in O(n). The Tree is not changed on error.
+func (t *Tree[Key, Value]) buildSlices(keys []Key, values []Value) (
+	err error) {
+
+	var (
+		i    int
+		root *node[Key, Value]
+	)
+	root, err = t.build(len(keys), func() (key Key, value Value, _ error) {
+		key, value = keys[i], values[i]
+		i++
+		return
+	})
+	if err != nil {
+		return
+	}
+	t.replace(root, len(keys))
+	return
+}
+
+// FromSorted creates the new RB-Tree from given keys and values in O(n),
+// which is much faster than n calls of Set. The keys must be sorted in
+// ascending order, otherwise ErrNotSorted returned. For duplicate keys
+// the last value is used. The keys and values must have the same length.
+func FromSorted[Key constraints.Ordered, Value any](keys []Key,
+	values []Value) (tr *Tree[Key, Value], err error) {
+
+	if tr, err = FromSortedFunc(cmp.Compare[Key], keys, values); err == nil {
+		tr.ordered = newOrdered[Key, Value]()
+	}
+	return
+}
+
+// FromSortedFunc is like the FromSorted, but the Tree uses given function
+// to compare keys. See also NewFunc.
+func FromSortedFunc[Key, Value any](cmp CompareFunc[Key], keys []Key,
+	values []Value) (tr *Tree[Key, Value], err error) {
+
+	if len(keys) != len(values) {
+		return nil, ErrLengthMismatch
+	}
+
+	var n = len(keys)
+	for i := 1; i < len(keys); i++ {
+		switch c := cmp(keys[i-1], keys[i]); {
+		case c == 0:
+			n-- // duplicate
+		case c > 0:
+			return nil, ErrNotSorted
+		}
+	}
+
+	tr = NewFunc[Key, Value](cmp)
+	var (
+		i    int
+		root *node[Key, Value]
+	)
+	root, err = tr.build(n, func() (key Key, value Value, _ error) {
+		for i+1 < len(keys) && cmp(keys[i], keys[i+1]) == 0 {
+			i++ // use the last one
+		}
+		key, value = keys[i], values[i]
+		i++
+		return
+	})
+	if err != nil {
+		return nil, err
+	}
+	tr.replace(root, n)
+	return
+}
+
+// FromSortedSeq creates the new RB-Tree from given sequence in O(n). The
+// sequence must be sorted in ascending order of keys, otherwise
+// ErrNotSorted returned. For duplicate keys the last value is used.
+func FromSortedSeq[Key constraints.Ordered, Value any](
+	seq iter.Seq2[Key, Value]) (tr *Tree[Key, Value], err error) {
+
+	if tr, err = FromSortedSeqFunc(cmp.Compare[Key], seq); err == nil {
+		tr.ordered = newOrdered[Key, Value]()
+	}
+	return
+}
+
+// FromSortedSeqFunc is like the FromSortedSeq, but the Tree uses given
+// function to compare keys. See also NewFunc.
+func FromSortedSeqFunc[Key, Value any](cmp CompareFunc[Key],
+	seq iter.Seq2[Key, Value]) (tr *Tree[Key, Value], err error) {
+
+	var (
+		keys   []Key
+		values []Value
+	)
+
+	for key, value := range seq {
+		if last := len(keys) - 1; last >= 0 {
+			switch c := cmp(keys[last], key); {
+			case c == 0:
+				values[last] = value
+				continue
+			case c > 0:
+				return nil, ErrNotSorted
+			}
+		}
+		keys, values = append(keys, key), append(values, value)
+	}
+
+	tr = NewFunc[Key, Value](cmp)
+	if err = tr.buildSlices(keys, values); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// lazyInit initializes a zero Tree, for example, created by a decoder.
+func (t *Tree[Key, Value]) lazyInit() error {
+	if t.sentinel != nil {
+		return nil
+	}
+	var cmp = orderedCompare[Key]()
+	if cmp == nil {
+		return ErrNotOrdered
+	}
+	t.sentinel = newSentinel[Key, Value]()
+	t.cmp = cmp
+	t.reset(t.sentinel, 0)
+	return nil
+}
+
+func compareAs[Key any, T cmp.Ordered]() CompareFunc[Key] {
+	return any(cmp.Compare[T]).(func(a, b Key) int)
+}
+
+// orderedCompare returns comparison function for ordered key type or nil.
+func orderedCompare[Key any]() CompareFunc[Key] {
+
+	var zero Key
+	switch any(zero).(type) {
+	case int:
+		return compareAs[Key, int]()
+	case int8:
+		return compareAs[Key, int8]()
+	case int16:
+		return compareAs[Key, int16]()
+	case int32:
+		return compareAs[Key, int32]()
+	case int64:
+		return compareAs[Key, int64]()
+	case uint:
+		return compareAs[Key, uint]()
+	case uint8:
+		return compareAs[Key, uint8]()
+	case uint16:
+		return compareAs[Key, uint16]()
+	case uint32:
+		return compareAs[Key, uint32]()
+	case uint64:
+		return compareAs[Key, uint64]()
+	case uintptr:
+		return compareAs[Key, uintptr]()
+	case float32:
+		return compareAs[Key, float32]()
+	case float64:
+		return compareAs[Key, float64]()
+	case string:
+		return compareAs[Key, string]()
+	}
+
+	// named types, slow
+	switch reflect.TypeFor[Key]().Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return func(a, b Key) int {
+			return cmp.Compare(reflect.ValueOf(a).Int(),
+				reflect.ValueOf(b).Int())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return func(a, b Key) int {
+			return cmp.Compare(reflect.ValueOf(a).Uint(),
+				reflect.ValueOf(b).Uint())
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(a, b Key) int {
+			return cmp.Compare(reflect.ValueOf(a).Float(),
+				reflect.ValueOf(b).Float())
+		}
+	case reflect.String:
+		return func(a, b Key) int {
+			return cmp.Compare(reflect.ValueOf(a).String(),
+				reflect.ValueOf(b).String())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,213 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree_build(t *testing.T) {
+
+	for n := 0; n < 300; n++ {
+		var (
+			tr     = New[int, int]()
+			keys   = make([]int, n)
+			values = make([]int, n)
+		)
+		for i := range keys {
+			keys[i], values[i] = i*2, -i
+		}
+		assert.NoError(t, tr.buildSlices(keys, values))
+		checkTree(t, tr)
+		assert.Equal(t, n, tr.Len())
+		for i, k := range keys {
+			assert.Equal(t, values[i], tr.Get(k))
+		}
+
+		// still valid after modifications
+		tr.Set(-1, 1)
+		tr.Del(n)
+		checkTree(t, tr)
+	}
+}
+
+func TestTree_buildErrors(t *testing.T) {
+
+	var tr = New[int, int]()
+	tr.Set(10, 10)
+
+	assert.ErrorIs(t, tr.buildSlices([]int{1, 2, 2}, []int{1, 2, 3}),
+		ErrNotSorted)
+	assert.ErrorIs(t, tr.buildSlices([]int{1, 3, 2}, []int{1, 2, 3}),
+		ErrNotSorted)
+
+	var errTest = errors.New("test")
+	var _, err = tr.build(3, func() (int, int, error) {
+		return 0, 0, errTest
+	})
+	assert.ErrorIs(t, err, errTest)
+
+	// not changed
+	assert.Equal(t, []int{10}, tr.SliceKeys(0, 100))
+	checkTree(t, tr)
+}
+
+func Test_orderedCompare(t *testing.T) {
+
+	type (
+		myInt    int16
+		myUint   uint8
+		myFloat  float32
+		myString string
+	)
+
+	assert.Equal(t, -1, orderedCompare[int]()(1, 2))
+	assert.Equal(t, 1, orderedCompare[int8]()(2, 1))
+	assert.Equal(t, 0, orderedCompare[int16]()(2, 2))
+	assert.Equal(t, -1, orderedCompare[int32]()(1, 2))
+	assert.Equal(t, -1, orderedCompare[int64]()(1, 2))
+	assert.Equal(t, -1, orderedCompare[uint]()(1, 2))
+	assert.Equal(t, -1, orderedCompare[uint8]()(1, 2))
+	assert.Equal(t, -1, orderedCompare[uint16]()(1, 2))
+	assert.Equal(t, -1, orderedCompare[uint32]()(1, 2))
+	assert.Equal(t, -1, orderedCompare[uint64]()(1, 2))
+	assert.Equal(t, -1, orderedCompare[uintptr]()(1, 2))
+	assert.Equal(t, -1, orderedCompare[float32]()(1, 2))
+	assert.Equal(t, -1, orderedCompare[float64]()(1, 2))
+	assert.Equal(t, -1, orderedCompare[string]()("a", "b"))
+
+	assert.Equal(t, -1, orderedCompare[myInt]()(-1, 2))
+	assert.Equal(t, 1, orderedCompare[myUint]()(3, 2))
+	assert.Equal(t, -1, orderedCompare[myFloat]()(1.5, 2))
+	assert.Equal(t, 1, orderedCompare[myString]()("b", "a"))
+
+	assert.Nil(t, orderedCompare[[]byte]())
+	assert.Nil(t, orderedCompare[struct{}]())
+}
+
+func TestTree_lazyInit(t *testing.T) {
+
+	var tr Tree[string, int]
+	assert.NoError(t, tr.lazyInit())
+	tr.Set("b", 2)
+	tr.Set("a", 1)
+	assert.Equal(t, []int{1, 2}, tr.Slice("a", "z"))
+	assert.NoError(t, tr.lazyInit()) // no-op
+	assert.Equal(t, 2, tr.Len())
+
+	var bt Tree[[]byte, int]
+	assert.ErrorIs(t, bt.lazyInit(), ErrNotOrdered)
+}
+
+func TestFromSorted(t *testing.T) {
+
+	var tr, err = FromSorted([]int{1, 2, 3}, []string{"a", "b", "c"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, tr.Slice(0, 10))
+	checkTree(t, tr)
+
+	// duplicates
+	tr, err = FromSorted([]int{1, 1, 2, 3, 3, 3}, []string{"a", "b", "c",
+		"d", "e", "f"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"b", "c", "f"}, tr.Slice(0, 10))
+	assert.Equal(t, 3, tr.Len())
+	checkTree(t, tr)
+
+	// empty
+	tr, err = FromSorted[int, string](nil, nil)
+	assert.NoError(t, err)
+	assert.Zero(t, tr.Len())
+	tr.Set(1, "ok")
+	checkTree(t, tr)
+
+	_, err = FromSorted([]int{2, 1}, []string{"a", "b"})
+	assert.ErrorIs(t, err, ErrNotSorted)
+	_, err = FromSorted([]int{1, 2}, []string{"a"})
+	assert.ErrorIs(t, err, ErrLengthMismatch)
+
+	// custom comparison
+	tr, err = FromSortedFunc(func(a, b int) int { return b - a },
+		[]int{3, 2, 1}, []string{"c", "b", "a"})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{3, 2, 1}, tr.SliceKeys(3, 1))
+	checkTree(t, tr)
+}
+
+func TestFromSortedSeq(t *testing.T) {
+
+	var src = New[int, int]()
+	for i := 0; i < Count; i++ {
+		src.Set(i*3, i)
+	}
+
+	var tr, err = FromSortedSeq(src.All())
+	assert.NoError(t, err)
+	assert.Equal(t, src.Len(), tr.Len())
+	assert.Equal(t, src.Slice(0, Count*3), tr.Slice(0, Count*3))
+	checkTree(t, tr)
+
+	var pairs = func(kv ...int) func(func(int, int) bool) {
+		return func(yield func(int, int) bool) {
+			for i := 0; i < len(kv); i += 2 {
+				if !yield(kv[i], kv[i+1]) {
+					return
+				}
+			}
+		}
+	}
+
+	tr, err = FromSortedSeq(pairs(1, 1, 1, 2, 2, 3))
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 3}, tr.Slice(0, 10))
+	checkTree(t, tr)
+
+	tr, err = FromSortedSeq(pairs())
+	assert.NoError(t, err)
+	assert.Zero(t, tr.Len())
+
+	_, err = FromSortedSeq(pairs(2, 2, 1, 1))
+	assert.ErrorIs(t, err, ErrNotSorted)
+
+	tr, err = FromSortedSeqFunc(func(a, b int) int { return b - a },
+		src.Backward())
+	assert.NoError(t, err)
+	assert.Equal(t, src.Len(), tr.Len())
+	checkTree(t, tr)
+}
@@ -0,0 +1,115 @@
+package rbtree
+
+import "sync/atomic"
+
+// gens is the last generation given to a Tree. A Tree owns nodes of its
+// generation only and changes them in place. Other nodes can be shared
+// with clones, and the Tree copies them before a change.
+var gens atomic.Uint64
+
+// Clone returns an independent copy of the Tree O(1). The Tree and the
+// copy share nodes, and both of them copy a shared node before changing
+// it. Thus, a change copies only nodes of its path O(logn), while nodes
+// it doesn't touch stay shared. An iterator step over a shared node of a
+// changed tree can take O(logn), because parent of the node belongs to
+// other tree. It's safe to clone the Tree and to change the clones in
+// different goroutines, while the Tree itself is not changed.
+func (t *Tree[Key, Value]) Clone() *Tree[Key, Value] {
+
+	var c = &Tree[Key, Value]{
+		sentinel: t.sentinel,
+		root:     t.root,
+		len:      t.len,
+		min:      t.min,
+		max:      t.max,
+		cmp:      t.cmp,
+		ordered:  t.ordered,
+		augment:  t.augment,
+		stale:    t.stale,
+	}
+	// both trees get new generations, thus none of them owns the nodes
+	c.gen.Store(gens.Add(1))
+	t.gen.Store(gens.Add(1))
+	return c
+}
+
+// copyNode returns copy of the shared n, that is owned by the Tree. The
+// copy replaces the n as the left or the right child of the parent, or as
+// the root, if the parent is nil.
+func (t *Tree[Key, Value]) copyNode(n, parent *node[Key, Value],
+	left bool) *node[Key, Value] {
+
+	var c = new(node[Key, Value])
+	*c = *n
+	c.gen, c.parent = t.gen.Load(), parent
+
+	switch {
+	case parent == nil:
+		t.root = c
+	case left:
+		parent.left = c
+	default:
+		parent.right = c
+	}
+
+	if n == t.min {
+		t.min = c
+	}
+	if n == t.max {
+		t.max = c
+	}
+	if n.left != t.sentinel || n.right != t.sentinel {
+		t.stale = true // parents of the children are not changed
+	}
+	return c
+}
+
+// ownRoot returns the root, that can be changed
+func (t *Tree[Key, Value]) ownRoot() *node[Key, Value] {
+	if t.root != t.sentinel && t.root.gen != t.gen.Load() {
+		return t.copyNode(t.root, nil, false)
+	}
+	return t.root
+}
+
+// ownLeft returns left child of the owned n, that can be changed
+func (t *Tree[Key, Value]) ownLeft(n *node[Key, Value]) (
+	l *node[Key, Value]) {
+
+	if l = n.left; l.gen != t.gen.Load() && l != t.sentinel {
+		l = t.copyNode(l, n, true)
+	}
+	return
+}
+
+// ownRight returns right child of the owned n, that can be changed
+func (t *Tree[Key, Value]) ownRight(n *node[Key, Value]) (
+	r *node[Key, Value]) {
+
+	if r = n.right; r.gen != t.gen.Load() && r != t.sentinel {
+		r = t.copyNode(r, n, false)
+	}
+	return
+}
+
+// own returns the n, that can be changed. A shared node is found by its
+// key again, copying nodes of the path O(logn).
+func (t *Tree[Key, Value]) own(n *node[Key, Value]) *node[Key, Value] {
+	if n.gen != t.gen.Load() && n != t.sentinel {
+		n, _, _ = t.descend(n.key, 0)
+	}
+	return n
+}
+
+// setParent sets parent of the x, if the Tree owns the x. The parent of a
+// shared node is not changed, because it's used by other trees.
+func (t *Tree[Key, Value]) setParent(x, parent *node[Key, Value]) {
+	if x == t.sentinel {
+		return
+	}
+	if x.gen == t.gen.Load() {
+		x.parent = parent
+		return
+	}
+	t.stale = true
+}
@@ -0,0 +1,302 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"maps"
+	"math/bits"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree_Clone(t *testing.T) {
+
+	var tr = New[int, string]()
+	tr.Set(1, "one")
+	tr.Set(2, "two")
+
+	var c = tr.Clone()
+	assert.True(t, c.root == tr.root, "shared")
+	assert.Equal(t, []string{"one", "two"}, c.Slice(0, 10))
+
+	c.Set(3, "three")
+	c.Set(1, "ONE")
+	assert.Equal(t, []string{"ONE", "two", "three"}, c.Slice(0, 10))
+	assert.Equal(t, []string{"one", "two"}, tr.Slice(0, 10))
+	checkTree(t, c)
+	checkTree(t, tr)
+
+	// copied nodes are owned, thus they are changed in place
+	tr.Del(2)
+	var root = tr.root
+	tr.Set(1, "uno")
+	assert.True(t, root == tr.root)
+	assert.Equal(t, []string{"uno"}, tr.Slice(0, 10))
+	assert.Equal(t, []string{"ONE", "two", "three"}, c.Slice(0, 10))
+
+	// clone of clone
+	var c1 = c.Clone()
+	var c2 = c1.Clone()
+	c1.Del(1)
+	c2.Move(2, 20)
+	c.Empty()
+	assert.Equal(t, 0, c.Len())
+	assert.Equal(t, []int{2, 3}, slices.Collect(c1.Keys()))
+	assert.Equal(t, []int{1, 3, 20}, slices.Collect(c2.Keys()))
+	checkTree(t, c1)
+	checkTree(t, c2)
+
+	// split and join
+	c1 = c2.Clone()
+	var left, right = c1.Split(3)
+	assert.Equal(t, []int{1}, slices.Collect(left.Keys()))
+	assert.Equal(t, []int{3, 20}, slices.Collect(right.Keys()))
+	assert.Equal(t, []int{1, 3, 20}, slices.Collect(c2.Keys()))
+	c1 = c2.Clone()
+	var other = New[int, string]()
+	other.Set(100, "hundred")
+	var joined, err = Join(c1, other)
+	assert.NoError(t, err)
+	joined.Set(0, "zero")
+	assert.Equal(t, []int{0, 1, 3, 20, 100}, slices.Collect(joined.Keys()))
+	assert.Equal(t, []int{1, 3, 20}, slices.Collect(c2.Keys()))
+	checkTree(t, joined)
+	checkTree(t, c2)
+}
+
+func TestTree_cloneConcurrent(t *testing.T) {
+
+	var base = New[int, int]()
+	for i := 0; i < 1000; i++ {
+		base.Set(i, i)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var c = base.Clone()
+			for j := 0; j < 1000; j += 2 {
+				c.Del(j)
+			}
+			c.Set(-1, i)
+			assert.Equal(t, 501, c.Len())
+			assert.Equal(t, i, c.Get(-1))
+			checkTree(t, c)
+		}(i)
+	}
+	for i := 0; i < 1000; i++ {
+		assert.Equal(t, i, base.Get(i)) // concurrent reads
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1000, base.Len())
+	checkTree(t, base)
+	base.Set(1000, 1000)
+	checkTree(t, base)
+}
+
+// copied returns number of nodes of the b, that the a doesn't have
+func copied[Key, Value any](a, b *Tree[Key, Value]) (n int) {
+	var nodes = make(map[*node[Key, Value]]bool)
+	var walk func(x *node[Key, Value], add bool)
+	walk = func(x *node[Key, Value], add bool) {
+		if x == a.sentinel {
+			return
+		}
+		if add {
+			nodes[x] = true
+		} else if !nodes[x] {
+			n++
+		}
+		walk(x.left, add)
+		walk(x.right, add)
+	}
+	walk(a.root, true)
+	walk(b.root, false)
+	return
+}
+
+func TestTree_clonePathCopy(t *testing.T) {
+
+	const n = 1 << 16
+	var tr = New[int, int]()
+	for i := 0; i < n; i++ {
+		tr.Set(i*2, i)
+	}
+	var limit = 4 * bits.Len(n) // two paths and siblings of fixups
+
+	var c = tr.Clone()
+	c.Set(-1, -1)
+	assert.Less(t, copied(tr, c), limit)
+	c.Set(n*2, n)
+	c.Del(n)
+	c.CompareAndSwap(1000, 500, 0)
+	c.PopMin()
+	assert.Less(t, copied(tr, c), 5*limit)
+	checkTree(t, c)
+	checkTree(t, tr)
+
+	// the clone iterates correctly over shared nodes, that have parents
+	// in the Tree
+	var keys = slices.Collect(c.Keys())
+	assert.Len(t, keys, n)
+	assert.True(t, slices.IsSorted(keys))
+	assert.Equal(t, 0, keys[0])
+	var desc []int
+	for key := range c.Range(n*2, n*2-6) {
+		desc = append(desc, key)
+	}
+	assert.Equal(t, []int{n * 2, n*2 - 2, n*2 - 4, n*2 - 6}, desc)
+	var it = c.Iter()
+	var back []int
+	for ok := it.SeekLast(); ok; ok = it.Prev() {
+		back = append(back, it.Key())
+	}
+	slices.Reverse(back)
+	assert.Equal(t, keys, back)
+
+	// bulk changes
+	var d = tr.Clone()
+	assert.Equal(t, 100, d.DeleteRange(1000, 1198))
+	d.PopMinN(10)
+	assert.NoError(t, d.WalkMut(0, 100, func(key int,
+		value *int) (Action, error) {
+
+		*value = -key
+		return Keep, nil
+	}))
+	assert.Less(t, copied(tr, d), 10*limit)
+	var left, right = d.Split(n)
+	assert.Equal(t, n-110, left.Len()+right.Len())
+	checkTree(t, left)
+	checkTree(t, right)
+
+	// the Tree itself is not changed
+	assert.Equal(t, n, tr.Len())
+	for i := 0; i < n; i++ {
+		assert.Equal(t, i, tr.Get(i*2))
+	}
+	checkTree(t, tr)
+}
+
+func TestTree_cloneRandom(t *testing.T) {
+
+	type version struct {
+		tree   *Tree[int, int]
+		oracle map[int]int
+	}
+
+	var (
+		rnd      = rand.New(rand.NewSource(1))
+		versions = []*version{{New[int, int](), make(map[int]int)}}
+	)
+
+	var check = func(v *version) {
+		t.Helper()
+		checkTree(t, v.tree)
+		var keys []int
+		for key, value := range v.tree.All() {
+			assert.Equal(t, v.oracle[key], value)
+			keys = append(keys, key)
+		}
+		assert.Len(t, keys, len(v.oracle))
+		var back []int
+		for key := range v.tree.Backward() {
+			back = append(back, key)
+		}
+		slices.Reverse(back)
+		assert.Equal(t, keys, back)
+	}
+
+	for i := 0; i < 5000; i++ {
+		var (
+			v   = versions[rnd.Intn(len(versions))]
+			key = rnd.Intn(500)
+		)
+		switch rnd.Intn(10) {
+		case 0:
+			if len(versions) < 20 {
+				versions = append(versions,
+					&version{v.tree.Clone(), maps.Clone(v.oracle)})
+			}
+		case 1:
+			v.tree.DeleteRange(key, key+20)
+			for k := range v.oracle {
+				if key <= k && k <= key+20 {
+					delete(v.oracle, k)
+				}
+			}
+		case 2:
+			v.tree.WalkMut(key, key+20, func(k int,
+				value *int) (Action, error) {
+
+				if k%3 == 0 {
+					delete(v.oracle, k)
+					return Delete, nil
+				}
+				*value = i
+				v.oracle[k] = i
+				return Keep, nil
+			})
+		case 3:
+			var l, r = v.tree.Split(key)
+			var err error
+			v.tree, err = Join(l, r)
+			assert.NoError(t, err)
+		case 4, 5:
+			v.tree.Del(key)
+			delete(v.oracle, key)
+		default:
+			v.tree.Set(key, i)
+			v.oracle[key] = i
+		}
+		if i%50 == 0 {
+			for _, v := range versions {
+				check(v)
+			}
+		}
+	}
+
+	for _, v := range versions {
+		check(v)
+	}
+}
@@ -0,0 +1,169 @@
+package rbtree
+
+import (
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// ErrNoCodec is returned when there is no default codec for a key or
+// a value type. Use Encode and Decode with own codecs.
+var ErrNoCodec = errors.New("no codec for the key or value type")
+
+// Codec encodes and decodes keys or values of a Tree.
+type Codec[T any] interface {
+	// Encode appends encoded v to the b and returns the extended buffer.
+	Encode(b []byte, v T) ([]byte, error)
+	// Decode decodes value from the data. The data must not be retained.
+	Decode(data []byte) (T, error)
+}
+
+// DefaultCodec returns default codec for given type. It returns false,
+// if there is no default codec. The default codec exists for
+//
+//   - types implementing encoding.BinaryMarshaler and
+//     encoding.BinaryUnmarshaler (by pointer), e.g. time.Time
+//   - booleans, integers, floats and strings
+//   - byte slices
+//
+// and for named types of them.
+func DefaultCodec[T any]() (Codec[T], bool) {
+
+	var zero T
+	if _, ok := any(zero).(encoding.BinaryMarshaler); ok {
+		if _, ok := any(&zero).(encoding.BinaryUnmarshaler); ok {
+			return binaryCodec[T]{}, true
+		}
+		var typ = reflect.TypeFor[T]()
+		if typ.Kind() == reflect.Pointer &&
+			typ.Implements(reflect.TypeFor[encoding.BinaryUnmarshaler]()) {
+			return binaryCodec[T]{}, true
+		}
+	}
+
+	switch typ := reflect.TypeFor[T](); typ.Kind() {
+	case reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64,
+		reflect.String:
+		return kindCodec[T]{}, true
+	case reflect.Slice:
+		if typ.Elem().Kind() == reflect.Uint8 {
+			return kindCodec[T]{}, true
+		}
+	}
+
+	return nil, false
+}
+
+// binaryCodec uses encoding.BinaryMarshaler and encoding.BinaryUnmarshaler
+type binaryCodec[T any] struct{}
+
+func (binaryCodec[T]) Encode(b []byte, v T) (_ []byte, err error) {
+	var data []byte
+	data, err = any(v).(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		return
+	}
+	return append(b, data...), nil
+}
+
+func (binaryCodec[T]) Decode(data []byte) (v T, err error) {
+	if u, ok := any(&v).(encoding.BinaryUnmarshaler); ok {
+		err = u.UnmarshalBinary(data)
+		return
+	}
+	// pointer type
+	var ptr = reflect.New(reflect.TypeFor[T]().Elem()).Interface()
+	err = ptr.(encoding.BinaryUnmarshaler).UnmarshalBinary(data)
+	if err != nil {
+		return
+	}
+	return ptr.(T), nil
+}
+
+// kindCodec encodes basic types and named types of them
+type kindCodec[T any] struct{}
+
+func (kindCodec[T]) Encode(b []byte, v T) ([]byte, error) {
+
+	var rv = reflect.ValueOf(&v).Elem()
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		if rv.Bool() {
+			return append(b, 1), nil
+		}
+		return append(b, 0), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		return binary.AppendVarint(b, rv.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		return binary.AppendUvarint(b, rv.Uint()), nil
+	case reflect.Float32:
+		return binary.BigEndian.AppendUint32(b,
+			math.Float32bits(float32(rv.Float()))), nil
+	case reflect.Float64:
+		return binary.BigEndian.AppendUint64(b,
+			math.Float64bits(rv.Float())), nil
+	case reflect.String:
+		return append(b, rv.String()...), nil
+	default: // byte slice
+		return append(b, rv.Bytes()...), nil
+	}
+}
+
+func (kindCodec[T]) Decode(data []byte) (v T, err error) {
+
+	var rv = reflect.ValueOf(&v).Elem()
+
+	switch rv.Kind() {
+	case reflect.Bool:
+		if len(data) != 1 || data[0] > 1 {
+			return v, invalidDataf("bad bool")
+		}
+		rv.SetBool(data[0] == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		var x, n = binary.Varint(data)
+		if n <= 0 || n != len(data) || rv.OverflowInt(x) {
+			return v, invalidDataf("bad integer")
+		}
+		rv.SetInt(x)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Uintptr:
+		var x, n = binary.Uvarint(data)
+		if n <= 0 || n != len(data) || rv.OverflowUint(x) {
+			return v, invalidDataf("bad unsigned integer")
+		}
+		rv.SetUint(x)
+	case reflect.Float32:
+		if len(data) != 4 {
+			return v, invalidDataf("bad float32")
+		}
+		rv.SetFloat(float64(math.Float32frombits(
+			binary.BigEndian.Uint32(data))))
+	case reflect.Float64:
+		if len(data) != 8 {
+			return v, invalidDataf("bad float64")
+		}
+		rv.SetFloat(math.Float64frombits(binary.BigEndian.Uint64(data)))
+	case reflect.String:
+		rv.SetString(string(data))
+	default: // byte slice
+		rv.SetBytes(append([]byte{}, data...))
+	}
+
+	return
+}
+
+func invalidDataf(format string, args ...any) error {
+	return fmt.Errorf("%w: "+format, append([]any{ErrInvalidData}, args...)...)
+}
@@ -0,0 +1,146 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"math"
+	"net/netip"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testCodec[T any](t *testing.T, values ...T) {
+	t.Helper()
+
+	var codec, ok = DefaultCodec[T]()
+	if !assert.True(t, ok) {
+		return
+	}
+	for _, v := range values {
+		var data, err = codec.Encode([]byte("prefix"), v)
+		if assert.NoError(t, err) {
+			var got T
+			got, err = codec.Decode(data[len("prefix"):])
+			assert.NoError(t, err)
+			assert.Equal(t, v, got)
+		}
+	}
+}
+
+func TestDefaultCodec(t *testing.T) {
+
+	type (
+		myInt   int8
+		myBytes []byte
+	)
+
+	testCodec(t, true, false)
+	testCodec(t, 0, 1, -1, math.MinInt, math.MaxInt)
+	testCodec[int8](t, math.MinInt8, math.MaxInt8)
+	testCodec[int16](t, math.MinInt16, math.MaxInt16)
+	testCodec[int32](t, math.MinInt32, math.MaxInt32)
+	testCodec[int64](t, math.MinInt64, math.MaxInt64)
+	testCodec[uint](t, 0, math.MaxUint)
+	testCodec[uint8](t, 0, math.MaxUint8)
+	testCodec[uint16](t, 0, math.MaxUint16)
+	testCodec[uint32](t, 0, math.MaxUint32)
+	testCodec[uint64](t, 0, math.MaxUint64)
+	testCodec[uintptr](t, 0, 1)
+	testCodec[float32](t, 0, -1.5, math.MaxFloat32)
+	testCodec(t, 0, -1.5, math.MaxFloat64, math.Inf(-1))
+	testCodec(t, "", "hello")
+	testCodec(t, []byte{}, []byte("hello"))
+	testCodec[myInt](t, -5, 5)
+	testCodec(t, myBytes("hello"))
+	testCodec(t, time.Date(2022, 8, 28, 18, 54, 57, 0, time.UTC))
+	testCodec(t, netip.MustParseAddr("10.0.0.1"))
+	testCodec(t, netip.MustParsePrefix("10.0.0.0/8"))
+
+	// pointer types
+	var u = &testPointerCodec{x: 10}
+	testCodec(t, u)
+
+	var _, ok = DefaultCodec[struct{}]()
+	assert.False(t, ok)
+	_, ok = DefaultCodec[[]int]()
+	assert.False(t, ok)
+	_, ok = DefaultCodec[map[int]int]()
+	assert.False(t, ok)
+}
+
+type testPointerCodec struct{ x byte }
+
+func (p *testPointerCodec) MarshalBinary() ([]byte, error) {
+	return []byte{p.x}, nil
+}
+
+func (p *testPointerCodec) UnmarshalBinary(data []byte) error {
+	p.x = data[0]
+	return nil
+}
+
+func TestDefaultCodec_invalid(t *testing.T) {
+
+	var boolCodec, _ = DefaultCodec[bool]()
+	var _, err = boolCodec.Decode([]byte{2})
+	assert.ErrorIs(t, err, ErrInvalidData)
+
+	var int8Codec, _ = DefaultCodec[int8]()
+	var intCodec, _ = DefaultCodec[int]()
+	var encoded, _ = intCodec.Encode(nil, 1000)
+	_, err = int8Codec.Decode(encoded)
+	assert.ErrorIs(t, err, ErrInvalidData)
+	_, err = int8Codec.Decode(nil)
+	assert.ErrorIs(t, err, ErrInvalidData)
+
+	var uint8Codec, _ = DefaultCodec[uint8]()
+	_, err = uint8Codec.Decode([]byte{0xff, 0xff, 0x03})
+	assert.ErrorIs(t, err, ErrInvalidData)
+
+	var float32Codec, _ = DefaultCodec[float32]()
+	_, err = float32Codec.Decode([]byte{1})
+	assert.ErrorIs(t, err, ErrInvalidData)
+
+	var float64Codec, _ = DefaultCodec[float64]()
+	_, err = float64Codec.Decode([]byte{1})
+	assert.ErrorIs(t, err, ErrInvalidData)
+
+	var timeCodec, _ = DefaultCodec[time.Time]()
+	_, err = timeCodec.Decode([]byte{1})
+	assert.Error(t, err)
+}
@@ -63,6 +63,7 @@ func Test_randomSetGetDel(t *testing.T) {
 		kv[k] = v
 		assert.Equal(t, len(kv), tr.Len())
 	}
+	checkTree(t, tr)
 
 	for k := range kv {
 		assert.Equal(t, kv[k], tr.Get(k))
@@ -223,6 +224,73 @@ func Test_randomSetWalk(t *testing.T) {
 	assert.Equal(t, count, len(kv))
 }
 
+// sorted-slice oracle for neighbour lookups
+func searchNeighbours(keys []int, key int) (floor, ceiling, lower,
+	higher int) {
+
+	floor, ceiling, lower, higher = -1, -1, -1, -1
+	for i, k := range keys {
+		if k < key {
+			lower = i
+		}
+		if k <= key {
+			floor = i
+		}
+		if k >= key && ceiling < 0 {
+			ceiling = i
+		}
+		if k > key && higher < 0 {
+			higher = i
+		}
+	}
+	return
+}
+
+func Test_randomNeighbours(t *testing.T) {
+
+	var (
+		tr   = New[int, int]()
+		keys []int
+	)
+
+	for i := 0; i < Count/10; i++ {
+		var k = rand.Intn(Count)
+		if tr.Set(k, -k) {
+			keys = append(keys, k)
+		}
+	}
+	qsort(keys)
+
+	var check = func(i int, key, value int, ok bool) {
+		if i < 0 {
+			assert.False(t, ok)
+			assert.Zero(t, key)
+			assert.Zero(t, value)
+			return
+		}
+		if assert.True(t, ok) {
+			assert.Equal(t, keys[i], key)
+			assert.Equal(t, -keys[i], value)
+		}
+	}
+
+	for i := 0; i < Count; i++ {
+		var (
+			k = rand.Intn(Count+2) - 1
+
+			floor, ceiling, lower, higher = searchNeighbours(keys, k)
+		)
+		var key, value, ok = tr.Floor(k)
+		check(floor, key, value, ok)
+		key, value, ok = tr.Ceiling(k)
+		check(ceiling, key, value, ok)
+		key, value, ok = tr.Lower(k)
+		check(lower, key, value, ok)
+		key, value, ok = tr.Higher(k)
+		check(higher, key, value, ok)
+	}
+}
+
 /*
 
 tree modification not allowed in a WalkFunc
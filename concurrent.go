@@ -0,0 +1,203 @@
+package rbtree
+
+import (
+	"cmp"
+	"iter"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/exp/constraints"
+)
+
+// TreeConcurrent is a thread-safe tree, readers of which never block and
+// never blocked. It keeps current version of a PersistentTree in an atomic
+// pointer. A reader loads the version and reads it without locks, even a
+// long Walk doesn't block writers. Writers are serialized by a mutex, and
+// every write publishes new version of the tree. Use the Update to apply
+// many changes at once.
+type TreeConcurrent[Key, Value any] struct {
+	mx   sync.Mutex // writers
+	tree atomic.Pointer[PersistentTree[Key, Value]]
+}
+
+// NewConcurrent creates the new empty TreeConcurrent.
+func NewConcurrent[Key constraints.Ordered,
+	Value any]() *TreeConcurrent[Key, Value] {
+
+	return NewConcurrentFunc[Key, Value](cmp.Compare[Key])
+}
+
+// NewConcurrentFunc creates the new empty TreeConcurrent, that uses given
+// function to compare keys. See also NewFunc.
+func NewConcurrentFunc[Key, Value any](
+	cmp CompareFunc[Key]) (tc *TreeConcurrent[Key, Value]) {
+
+	tc = new(TreeConcurrent[Key, Value])
+	tc.tree.Store(NewPersistentFunc[Key, Value](cmp))
+	return
+}
+
+// Snapshot returns current version of the tree O(1). The version is
+// never changed.
+func (t *TreeConcurrent[Key, Value]) Snapshot() *PersistentTree[Key, Value] {
+	return t.tree.Load()
+}
+
+// Update calls given function with current version of the tree and
+// publishes the returned one. Other writers are blocked during the call,
+// and readers see all changes made by the function at once.
+//
+//	tc.Update(func(p *rbtree.PersistentTree[int, string]) *rbtree.PersistentTree[int, string] {
+//	    return p.Set(1, "one").Set(2, "two").Del(3)
+//	})
+func (t *TreeConcurrent[Key, Value]) Update(
+	fn func(p *PersistentTree[Key, Value]) *PersistentTree[Key, Value]) {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.tree.Store(fn(t.tree.Load()))
+}
+
+// Set the value. O(logn). This will overwrite the existing value.
+func (t *TreeConcurrent[Key, Value]) Set(key Key, value Value) (added bool) {
+	t.Update(func(p *PersistentTree[Key, Value]) *PersistentTree[Key, Value] {
+		var q = p.Set(key, value)
+		added = q.len > p.len
+		return q
+	})
+	return
+}
+
+// SetNx doesn't overwrites an existing value.
+func (t *TreeConcurrent[Key, Value]) SetNx(key Key,
+	value Value) (added bool) {
+
+	t.Update(func(p *PersistentTree[Key, Value]) *PersistentTree[Key, Value] {
+		if p.IsExist(key) {
+			return p
+		}
+		added = true
+		return p.Set(key, value)
+	})
+	return
+}
+
+// Del deletes value by key. O(logn). It returns false,
+// if key doesn't exits.
+func (t *TreeConcurrent[Key, Value]) Del(key Key) (deleted bool) {
+	t.Update(func(p *PersistentTree[Key, Value]) *PersistentTree[Key, Value] {
+		var q = p.Del(key)
+		deleted = q != p
+		return q
+	})
+	return
+}
+
+// Move moves the value from one index to another. Silent.
+// It just changes index of value O(2logn).
+func (t *TreeConcurrent[Key, Value]) Move(oldKey, newKey Key) (moved bool) {
+	t.Update(func(p *PersistentTree[Key, Value]) *PersistentTree[Key, Value] {
+		var value, ok = p.GetEx(oldKey)
+		if !ok {
+			return p
+		}
+		moved = true
+		return p.Del(oldKey).Set(newKey, value)
+	})
+	return
+}
+
+// Empty makes the tree empty O(1).
+func (t *TreeConcurrent[Key, Value]) Empty() {
+	t.Update(func(p *PersistentTree[Key, Value]) *PersistentTree[Key, Value] {
+		return NewPersistentFunc[Key, Value](p.cmp)
+	})
+}
+
+// Get O(logn). It returns zero value, if key doesn't exist.
+func (t *TreeConcurrent[Key, Value]) Get(key Key) Value {
+	return t.tree.Load().Get(key)
+}
+
+// GetEx O(logn). It returns false, if key doesn't exist.
+func (t *TreeConcurrent[Key, Value]) GetEx(key Key) (Value, bool) {
+	return t.tree.Load().GetEx(key)
+}
+
+// IsExist O(logn)
+func (t *TreeConcurrent[Key, Value]) IsExist(key Key) bool {
+	return t.tree.Load().IsExist(key)
+}
+
+// Floor returns the greatest key less than or equal to the given one and
+// its value O(logn). It returns false, if there is no such key.
+func (t *TreeConcurrent[Key, Value]) Floor(key Key) (Key, Value, bool) {
+	return t.tree.Load().Floor(key)
+}
+
+// Ceiling returns the smallest key greater than or equal to the given one
+// and its value O(logn). It returns false, if there is no such key.
+func (t *TreeConcurrent[Key, Value]) Ceiling(key Key) (Key, Value, bool) {
+	return t.tree.Load().Ceiling(key)
+}
+
+// Lower returns the greatest key strictly less than the given one and
+// its value O(logn). It returns false, if there is no such key.
+func (t *TreeConcurrent[Key, Value]) Lower(key Key) (Key, Value, bool) {
+	return t.tree.Load().Lower(key)
+}
+
+// Higher returns the smallest key strictly greater than the given one and
+// its value O(logn). It returns false, if there is no such key.
+func (t *TreeConcurrent[Key, Value]) Higher(key Key) (Key, Value, bool) {
+	return t.tree.Load().Higher(key)
+}
+
+// Len O(1)
+func (t *TreeConcurrent[Key, Value]) Len() int {
+	return t.tree.Load().Len()
+}
+
+// Max returns maximum index and its value O(logn)
+func (t *TreeConcurrent[Key, Value]) Max() (Key, Value) {
+	return t.tree.Load().Max()
+}
+
+// Min returns minimum indexed and its value O(logn)
+func (t *TreeConcurrent[Key, Value]) Min() (Key, Value) {
+	return t.tree.Load().Min()
+}
+
+// Walk on current version of the tree. See Tree.Walk for details. The
+// walking doesn't block writers, and it's safe to change the tree inside
+// the WalkFunc, the changes are not visible for the walking.
+func (t *TreeConcurrent[Key, Value]) Walk(from, to Key,
+	walkFunc WalkFunc[Key, Value]) error {
+
+	return t.tree.Load().Walk(from, to, walkFunc)
+}
+
+// Slice returns all values at given range if any.
+func (t *TreeConcurrent[Key, Value]) Slice(from, to Key) []Value {
+	return t.tree.Load().Slice(from, to)
+}
+
+// SliceKeys returns all keys at given range if any.
+func (t *TreeConcurrent[Key, Value]) SliceKeys(from, to Key) []Key {
+	return t.tree.Load().SliceKeys(from, to)
+}
+
+// All returns an iterator over all key-value pairs of current version of
+// the tree in ascending order of keys. The loop doesn't block writers.
+func (t *TreeConcurrent[Key, Value]) All() iter.Seq2[Key, Value] {
+	return t.tree.Load().All()
+}
+
+// Range returns an iterator over key-value pairs of current version of
+// the tree at given range. See Tree.Range for details.
+func (t *TreeConcurrent[Key, Value]) Range(from,
+	to Key) iter.Seq2[Key, Value] {
+
+	return t.tree.Load().Range(from, to)
+}
@@ -0,0 +1,152 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTreeConcurrent(t *testing.T) {
+
+	var tr TreeInterface[int, string] = NewConcurrent[int, string]()
+
+	assert.True(t, tr.Set(1, "one"))
+	assert.False(t, tr.Set(1, "ONE"))
+	assert.True(t, tr.SetNx(2, "two"))
+	assert.False(t, tr.SetNx(2, "TWO"))
+	assert.True(t, tr.Set(3, "three"))
+	assert.Equal(t, 3, tr.Len())
+	assert.Equal(t, "ONE", tr.Get(1))
+	assert.Equal(t, []string{"ONE", "two", "three"}, tr.Slice(0, 10))
+	assert.Equal(t, []int{3, 2, 1}, tr.SliceKeys(10, 0))
+
+	var key, value, ok = tr.Floor(10)
+	assert.True(t, ok)
+	assert.Equal(t, 3, key)
+	assert.Equal(t, "three", value)
+	_, _, ok = tr.Higher(3)
+	assert.False(t, ok)
+	key, _, ok = tr.Lower(3)
+	assert.True(t, ok)
+	assert.Equal(t, 2, key)
+	key, _, ok = tr.Ceiling(0)
+	assert.True(t, ok)
+	assert.Equal(t, 1, key)
+
+	assert.True(t, tr.Move(3, 30))
+	assert.False(t, tr.Move(3, 30))
+	assert.True(t, tr.IsExist(30))
+	assert.True(t, tr.Del(30))
+	assert.False(t, tr.Del(30))
+
+	key, value = tr.Max()
+	assert.Equal(t, 2, key)
+	assert.Equal(t, "two", value)
+	key, value = tr.Min()
+	assert.Equal(t, 1, key)
+	assert.Equal(t, "ONE", value)
+
+	tr.Empty()
+	assert.Equal(t, 0, tr.Len())
+}
+
+func TestTreeConcurrent_snapshot(t *testing.T) {
+
+	var tc = NewConcurrent[int, int]()
+	tc.Update(func(p *PersistentTree[int, int]) *PersistentTree[int, int] {
+		for i := 0; i < 10; i++ {
+			p = p.Set(i, i)
+		}
+		return p
+	})
+
+	var snap = tc.Snapshot()
+	tc.Del(0)
+	assert.Equal(t, 10, snap.Len())
+	assert.Equal(t, 9, tc.Len())
+
+	// changes inside the walking are not visible for the walking
+	var walked int
+	var err = tc.Walk(0, 100, func(key, value int) error {
+		tc.Del(key)
+		walked++
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 9, walked)
+	assert.Equal(t, 0, tc.Len())
+
+	for range tc.All() {
+		t.Error("not empty")
+	}
+}
+
+func TestTreeConcurrent_race(t *testing.T) {
+
+	var (
+		tc = NewConcurrent[int, int]()
+		wg sync.WaitGroup
+	)
+
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				tc.Set(j, i)
+				if j%3 == 0 {
+					tc.Del(j - 1)
+				}
+			}
+		}(i)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				var snap = tc.Snapshot()
+				var n int
+				for range tc.Range(0, 1000) {
+					n++
+				}
+				checkPersistent(t, snap)
+			}
+		}()
+	}
+	wg.Wait()
+	checkPersistent(t, tc.Snapshot())
+}
@@ -0,0 +1,42 @@
+package rbtree
+
+// DeleteRange deletes all keys in given inclusive range O(logn) and
+// returns number of deleted keys. The order of the from and the to
+// doesn't matter.
+func (t *Tree[Key, Value]) DeleteRange(from, to Key) (deleted int) {
+
+	if t.cmp(from, to) > 0 {
+		from, to = to, from
+	}
+	if t.CountRange(from, to) == 0 {
+		return
+	}
+
+	var (
+		l, lh, r, rh = t.split(t.ownRoot(), t.blackHeight(t.root), from,
+			false)
+		m, _, rr, _ = t.split(r, rh, to, true)
+	)
+
+	t.reset(t.concat(l, rr, lh), t.len-m.size)
+	t.recordCut(m)
+	return m.size
+}
+
+// DeleteFunc deletes all keys for which given function returns true and
+// returns number of deleted keys. It takes O(n+klogn), where k is number
+// of deleted keys. The Tree shouldn't be used inside the function.
+func (t *Tree[Key, Value]) DeleteFunc(del func(key Key,
+	value Value) bool) (deleted int) {
+
+	var keys []Key
+	for x := t.minimum(t.root); x != t.sentinel; x = t.successor(x) {
+		if del(x.key, x.value) {
+			keys = append(keys, x.key)
+		}
+	}
+	for _, key := range keys {
+		t.Del(key)
+	}
+	return len(keys)
+}
@@ -0,0 +1,136 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree_DeleteRange(t *testing.T) {
+
+	var tr = New[int, int]()
+	for i := 0; i < 10; i++ {
+		tr.Set(i*10, i)
+	}
+
+	assert.Equal(t, 3, tr.DeleteRange(15, 45))
+	assert.Equal(t, []int{0, 10, 50, 60, 70, 80, 90}, slices.Collect(tr.Keys()))
+	assert.Equal(t, 2, tr.DeleteRange(90, 80)) // reversed, inclusive
+	assert.Equal(t, 0, tr.DeleteRange(11, 49))
+	assert.Equal(t, 1, tr.DeleteRange(-100, 0))
+	assert.Equal(t, []int{10, 50, 60, 70}, slices.Collect(tr.Keys()))
+	assert.Equal(t, 4, tr.Len())
+	checkTree(t, tr)
+	assert.Equal(t, 4, tr.DeleteRange(-100, 100))
+	assert.Equal(t, 0, tr.Len())
+	checkTree(t, tr)
+}
+
+func Test_randomDeleteRange(t *testing.T) {
+
+	var rnd = rand.New(rand.NewSource(42))
+
+	for i := 0; i < 500; i++ {
+		var (
+			tr   = New[int, int]()
+			want = map[int]bool{}
+		)
+		for j := rnd.Intn(300); j >= 0; j-- {
+			var key = rnd.Intn(1000)
+			tr.Set(key, key)
+			want[key] = true
+		}
+		var from, to = rnd.Intn(1100) - 50, rnd.Intn(1100) - 50
+		var n int
+		for key := range want {
+			if key >= min(from, to) && key <= max(from, to) {
+				delete(want, key)
+				n++
+			}
+		}
+		if !assert.Equal(t, n, tr.DeleteRange(from, to)) {
+			return
+		}
+		checkTree(t, tr)
+		assert.Equal(t, len(want), tr.Len())
+		for key := range tr.Keys() {
+			assert.True(t, want[key])
+		}
+	}
+}
+
+func TestTree_DeleteFunc(t *testing.T) {
+
+	var tr = New[int, int]()
+	for i := 0; i < 100; i++ {
+		tr.Set(i, i*i)
+	}
+
+	var deleted = tr.DeleteFunc(func(key, value int) bool {
+		return key%2 == 1 || value > 1000
+	})
+	assert.Equal(t, 84, deleted)
+	assert.Equal(t, []int{0, 2, 4, 6, 8, 10, 12, 14, 16, 18, 20, 22, 24, 26,
+		28, 30}, slices.Collect(tr.Keys()))
+	checkTree(t, tr)
+	assert.Zero(t, tr.DeleteFunc(func(int, int) bool { return false }))
+}
+
+func TestTreeThreadSafe_DeleteRange(t *testing.T) {
+
+	var tts = NewThreadSafe[int, int]()
+	for i := 0; i < 100; i++ {
+		tts.Set(i, i)
+	}
+
+	var err = tts.Update(func(tx *Tree[int, int]) error {
+		tx.DeleteRange(10, 90)
+		tx.DeleteFunc(func(key, _ int) bool { return key < 5 })
+		return errors.New("rollback")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 100, tts.Len())
+	checkTree(t, tts.tree)
+
+	assert.Equal(t, 81, tts.DeleteRange(10, 90))
+	assert.Equal(t, 5, tts.DeleteFunc(func(key, _ int) bool { return key < 5 }))
+	assert.Equal(t, 14, tts.Len())
+}
@@ -0,0 +1,197 @@
+package rbtree
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"reflect"
+)
+
+// jsonPair is JSON representation of an element of a Tree
+// with not string key
+type jsonPair[Key, Value any] struct {
+	Key   Key   `json:"k"`
+	Value Value `json:"v"`
+}
+
+// isStringKey returns true if the key type is a string or a named string
+func isStringKey[Key any]() bool {
+	return reflect.TypeFor[Key]().Kind() == reflect.String
+}
+
+// MarshalJSON implements json.Marshaler interface. A Tree with string keys
+// is encoded as JSON object, keys of the object are in ascending order.
+// Otherwise, the Tree is encoded as array of {"k": key, "v": value}
+// objects in ascending order of keys.
+func (t *Tree[Key, Value]) MarshalJSON() (_ []byte, err error) {
+
+	var (
+		buf      bytes.Buffer
+		isObject = isStringKey[Key]()
+		data     []byte
+	)
+
+	if isObject {
+		buf.WriteByte('{')
+	} else {
+		buf.WriteByte('[')
+	}
+
+	// the min and the sentinel of a zero Tree are nil, that is it's empty
+	for x := t.min; x != t.sentinel; x = t.successor(x) {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		if isObject {
+			data, err = json.Marshal(reflect.ValueOf(x.key).String())
+			if err != nil {
+				return
+			}
+			buf.Write(data)
+			buf.WriteByte(':')
+			data, err = json.Marshal(x.value)
+		} else {
+			data, err = json.Marshal(jsonPair[Key, Value]{x.key, x.value})
+		}
+		if err != nil {
+			return
+		}
+		buf.Write(data)
+	}
+
+	if isObject {
+		buf.WriteByte('}')
+	} else {
+		buf.WriteByte(']')
+	}
+
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface. It accepts both
+// forms produced by the MarshalJSON and replaces content of the Tree. The
+// JSON object form is allowed for string keys only. For duplicate keys the
+// last value is used. The Tree is not changed on error.
+func (t *Tree[Key, Value]) UnmarshalJSON(data []byte) (err error) {
+
+	if err = t.lazyInit(); err != nil {
+		return
+	}
+
+	var (
+		keys   []Key
+		values []Value
+	)
+
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return // no-op by convention
+	}
+	if len(data) > 0 && data[0] == '{' && isStringKey[Key]() {
+		keys, values, err = unmarshalJSONObject[Key, Value](data)
+		if err != nil {
+			return
+		}
+	} else {
+		var pairs []jsonPair[Key, Value]
+		if err = json.Unmarshal(data, &pairs); err != nil {
+			return
+		}
+		keys = make([]Key, 0, len(pairs))
+		values = make([]Value, 0, len(pairs))
+		for _, p := range pairs {
+			keys, values = append(keys, p.Key), append(values, p.Value)
+		}
+	}
+
+	return t.load(keys, values)
+}
+
+func unmarshalJSONObject[Key, Value any](data []byte) (keys []Key,
+	values []Value, err error) {
+
+	var dec = json.NewDecoder(bytes.NewReader(data))
+	if _, err = dec.Token(); err != nil { // {
+		return
+	}
+
+	for dec.More() {
+		var (
+			tok   json.Token
+			key   Key
+			value Value
+		)
+		if tok, err = dec.Token(); err != nil {
+			return
+		}
+		reflect.ValueOf(&key).Elem().SetString(tok.(string))
+		if err = dec.Decode(&value); err != nil {
+			return
+		}
+		keys, values = append(keys, key), append(values, value)
+	}
+
+	_, err = dec.Token() // }
+	return
+}
+
+// load replaces content of the Tree with given keys and values. If the
+// keys are sorted, then it takes O(n), otherwise O(nlogn). For duplicate
+// keys the last value is used. The Tree is not changed on error.
+func (t *Tree[Key, Value]) load(keys []Key, values []Value) (err error) {
+
+	if err = t.buildSlices(keys, values); err != ErrNotSorted {
+		return
+	}
+
+	var tmp = t.newTree()
+	for i, key := range keys {
+		tmp.Set(key, values[i])
+	}
+	t.replace(tmp.root, tmp.len)
+	return nil
+}
+
+// gobTree is gob representation of a Tree
+type gobTree[Key, Value any] struct {
+	Keys   []Key
+	Values []Value
+}
+
+// GobEncode implements gob.GobEncoder interface.
+func (t *Tree[Key, Value]) GobEncode() (_ []byte, err error) {
+
+	var gt = gobTree[Key, Value]{
+		Keys:   make([]Key, 0, t.len),
+		Values: make([]Value, 0, t.len),
+	}
+	for x := t.min; x != t.sentinel; x = t.successor(x) {
+		gt.Keys = append(gt.Keys, x.key)
+		gt.Values = append(gt.Values, x.value)
+	}
+
+	var buf bytes.Buffer
+	if err = gob.NewEncoder(&buf).Encode(&gt); err != nil {
+		return
+	}
+	return buf.Bytes(), nil
+}
+
+// GobDecode implements gob.GobDecoder interface. It replaces content of
+// the Tree. The Tree is not changed on error.
+func (t *Tree[Key, Value]) GobDecode(data []byte) (err error) {
+
+	if err = t.lazyInit(); err != nil {
+		return
+	}
+
+	var gt gobTree[Key, Value]
+	if err = gob.NewDecoder(bytes.NewReader(data)).Decode(&gt); err != nil {
+		return
+	}
+	if len(gt.Keys) != len(gt.Values) {
+		return invalidDataf("keys and values length mismatch")
+	}
+
+	return t.load(gt.Keys, gt.Values)
+}
@@ -0,0 +1,247 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"math"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	_ json.Marshaler   = (*Tree[int, string])(nil)
+	_ json.Unmarshaler = (*Tree[int, string])(nil)
+	_ gob.GobEncoder   = (*Tree[int, string])(nil)
+	_ gob.GobDecoder   = (*Tree[int, string])(nil)
+	_ json.Marshaler   = (*TreeThreadSafe[int, string])(nil)
+	_ json.Unmarshaler = (*TreeThreadSafe[int, string])(nil)
+	_ gob.GobEncoder   = (*TreeThreadSafe[int, string])(nil)
+	_ gob.GobDecoder   = (*TreeThreadSafe[int, string])(nil)
+)
+
+func TestTree_MarshalJSON(t *testing.T) {
+
+	var tr = New[int, string]()
+
+	var data, err = json.Marshal(tr)
+	assert.NoError(t, err)
+	assert.Equal(t, `[]`, string(data))
+
+	tr.Set(2, "two")
+	tr.Set(1, "one")
+	tr.Set(3, "three")
+
+	data, err = json.Marshal(tr)
+	assert.NoError(t, err)
+	assert.Equal(t, `[{"k":1,"v":"one"},{"k":2,"v":"two"},`+
+		`{"k":3,"v":"three"}]`, string(data))
+
+	var got = New[int, string]()
+	got.Set(10, "ten")
+	assert.NoError(t, json.Unmarshal(data, got))
+	assert.Equal(t, []int{1, 2, 3}, got.SliceKeys(math.MinInt, math.MaxInt))
+	assert.Equal(t, "two", got.Get(2))
+
+	// not sorted, duplicates
+	assert.NoError(t, json.Unmarshal([]byte(`[{"k":2,"v":"b"},`+
+		`{"k":1,"v":"a"},{"k":2,"v":"c"}]`), got))
+	assert.Equal(t, []string{"a", "c"}, got.Slice(0, 10))
+	checkTree(t, got)
+
+	// null is no-op
+	assert.NoError(t, json.Unmarshal([]byte(`null`), got))
+	assert.Equal(t, 2, got.Len())
+
+	// not changed on error
+	assert.Error(t, json.Unmarshal([]byte(`{"k":2}`), got))
+	assert.Error(t, json.Unmarshal([]byte(`[{"k":"x","v":"y"}]`), got))
+	assert.Equal(t, []string{"a", "c"}, got.Slice(0, 10))
+}
+
+func TestTree_MarshalJSON_stringKeys(t *testing.T) {
+
+	type name string
+
+	var tr = New[name, int]()
+
+	var data, err = json.Marshal(tr)
+	assert.NoError(t, err)
+	assert.Equal(t, `{}`, string(data))
+
+	tr.Set("b", 2)
+	tr.Set("a", 1)
+	tr.Set("c\"", 3)
+
+	data, err = json.Marshal(tr)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"a":1,"b":2,"c\"":3}`, string(data))
+
+	var got = New[name, int]()
+	assert.NoError(t, json.Unmarshal(data, got))
+	assert.Equal(t, []int{1, 2, 3}, got.Slice("", "z"))
+	assert.Equal(t, 3, got.Get("c\""))
+
+	// array form is accepted too
+	assert.NoError(t, json.Unmarshal([]byte(`[{"k":"x","v":10}]`), got))
+	assert.Equal(t, []name{"x"}, got.SliceKeys("", "z"))
+
+	// not changed on error
+	assert.Error(t, json.Unmarshal([]byte(`{"a":"x"}`), got))
+	assert.Error(t, json.Unmarshal([]byte(`{"a":1,}`), got))
+	assert.Equal(t, []name{"x"}, got.SliceKeys("", "z"))
+}
+
+func TestTree_MarshalJSON_embedded(t *testing.T) {
+
+	type response struct {
+		Scores *Tree[string, int]             `json:"scores"`
+		Ranks  *TreeThreadSafe[float64, bool] `json:"ranks"`
+	}
+
+	var resp = response{
+		Scores: New[string, int](),
+		Ranks:  NewThreadSafe[float64, bool](),
+	}
+	resp.Scores.Set("zed", 10)
+	resp.Scores.Set("amy", 20)
+	resp.Ranks.Set(0.5, true)
+
+	var data, err = json.Marshal(resp)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"scores":{"amy":20,"zed":10},`+
+		`"ranks":[{"k":0.5,"v":true}]}`, string(data))
+
+	var got response
+	assert.NoError(t, json.Unmarshal(data, &got))
+	assert.Equal(t, 20, got.Scores.Get("amy"))
+	assert.True(t, got.Ranks.Get(0.5))
+	checkTree(t, got.Scores)
+
+	// not ordered key of zero tree
+	var bt Tree[[]byte, int]
+	assert.ErrorIs(t, json.Unmarshal([]byte(`[]`), &bt), ErrNotOrdered)
+}
+
+func TestTree_MarshalJSON_zero(t *testing.T) {
+
+	type response struct {
+		Scores Tree[string, int]
+		Ranks  Tree[float64, bool]
+		Safe   TreeThreadSafe[int, string]
+	}
+
+	var data, err = json.Marshal(&response{})
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Scores":{},"Ranks":[],"Safe":[]}`, string(data))
+
+	var got response
+	assert.NoError(t, json.Unmarshal(data, &got))
+	assert.Zero(t, got.Scores.Len())
+	assert.Zero(t, got.Ranks.Len())
+	assert.Zero(t, got.Safe.Len())
+
+	got.Scores.Set("amy", 20)
+	got.Safe.Set(1, "one")
+	data, err = json.Marshal(&got)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"Scores":{"amy":20},"Ranks":[],"Safe":[{"k":1,`+
+		`"v":"one"}]}`, string(data))
+
+	// not ordered key
+	var bt Tree[[]byte, int]
+	data, err = bt.MarshalJSON()
+	assert.NoError(t, err)
+	assert.Equal(t, `[]`, string(data))
+}
+
+func TestTree_GobEncode(t *testing.T) {
+
+	var tr = New[int, string]()
+	for i := 0; i < Count; i++ {
+		tr.Set(rand.Intn(Count*10), "x")
+	}
+
+	type snapshot struct {
+		Tree *Tree[int, string]
+		Safe *TreeThreadSafe[int, string]
+	}
+
+	var (
+		buf bytes.Buffer
+		src = snapshot{Tree: tr, Safe: ToThreadSafe(tr)}
+	)
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(&src))
+
+	var got snapshot
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(&got))
+	assert.Equal(t, tr.SliceKeys(math.MinInt, math.MaxInt),
+		got.Tree.SliceKeys(math.MinInt, math.MaxInt))
+	assert.Equal(t, tr.Len(), got.Safe.Len())
+	checkTree(t, got.Tree)
+
+	// empty
+	buf.Reset()
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(New[int, string]()))
+	var empty = New[int, string]()
+	empty.Set(1, "one")
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(empty))
+	assert.Zero(t, empty.Len())
+
+	// zero
+	type config struct {
+		Tree Tree[int, string]
+		Safe TreeThreadSafe[int, string]
+	}
+	buf.Reset()
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(&config{}))
+	var zero config
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(&zero))
+	assert.Zero(t, zero.Tree.Len())
+	assert.Zero(t, zero.Safe.Len())
+	checkTree(t, &zero.Tree)
+
+	// errors
+	assert.Error(t, empty.GobDecode([]byte("garbage")))
+	var gt = gobTree[int, string]{Keys: []int{1}}
+	buf.Reset()
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(&gt))
+	assert.ErrorIs(t, empty.GobDecode(buf.Bytes()), ErrInvalidData)
+}
@@ -36,6 +36,7 @@
 package rbtree
 
 import (
+	"bytes"
 	"fmt"
 	"math"
 )
@@ -159,3 +160,185 @@ func ExampleTree_Walk() {
 	// 2 - two
 	// 3 - three
 }
+
+func ExampleTree_Iter() {
+	var tr = New[int, string]()
+	tr.Set(1, "one")
+	tr.Set(2, "two")
+	tr.Set(3, "three")
+	var it = tr.Iter()
+	for ok := it.Seek(2); ok; ok = it.Next() {
+		fmt.Println(it.Key(), "-", it.Value())
+	}
+	for ok := it.SeekLast(); ok; ok = it.Prev() {
+		fmt.Println(it.Key(), "-", it.Value())
+	}
+	// Output:
+	// 2 - two
+	// 3 - three
+	// 3 - three
+	// 2 - two
+	// 1 - one
+}
+
+func ExampleTree_Range() {
+	var tr = New[int, string]()
+	tr.Set(1, "one")
+	tr.Set(2, "two")
+	tr.Set(3, "three")
+	for key, value := range tr.Range(3, 2) {
+		fmt.Println(key, "-", value)
+	}
+	// Output:
+	// 3 - three
+	// 2 - two
+}
+
+func ExampleTree_Floor() {
+	var tr = New[int, string]()
+	tr.Set(10, "ten")
+	tr.Set(20, "twenty")
+	fmt.Println(tr.Floor(15))
+	fmt.Println(tr.Ceiling(15))
+	fmt.Println(tr.Lower(10))
+	// Output:
+	// 10 ten true
+	// 20 twenty true
+	// 0  false
+}
+
+func ExampleTree_Rank() {
+	var tr = New[int, string]()
+	tr.Set(10, "ten")
+	tr.Set(20, "twenty")
+	tr.Set(30, "thirty")
+	fmt.Println(tr.Rank(20))
+	fmt.Println(tr.Select(2))
+	fmt.Println(tr.CountRange(0, 25))
+	// Output:
+	// 1
+	// 30 thirty true
+	// 2
+}
+
+func ExampleNewFunc() {
+	var tr = NewFunc[[]byte, string](bytes.Compare)
+	tr.Set([]byte("b"), "two")
+	tr.Set([]byte("a"), "one")
+	fmt.Println(tr.Slice([]byte("a"), []byte("z")))
+	// Output:
+	// [one two]
+}
+
+func ExampleTree_MarshalBinary() {
+	var tr = New[int, string]()
+	tr.Set(1, "one")
+	tr.Set(2, "two")
+	var data, err = tr.MarshalBinary()
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	var restored = New[int, string]()
+	if err = restored.UnmarshalBinary(data); err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(restored.Slice(0, 10))
+	// Output:
+	// [one two]
+}
+
+func ExampleFromSorted() {
+	var tr, err = FromSorted([]int{1, 2, 3}, []string{"one", "two", "three"})
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Println(tr.Len(), tr.Get(2))
+	// Output:
+	// 3 two
+}
+
+func ExampleUnion() {
+	var a, b = New[int, int](), New[int, int]()
+	a.Set(1, 10)
+	a.Set(2, 20)
+	b.Set(2, 2)
+	b.Set(3, 3)
+	var sum = func(key int, x, y int) int { return x + y }
+	fmt.Println(Union(a, b, sum).Slice(0, 10))
+	fmt.Println(Intersect(a, b, sum).Slice(0, 10))
+	fmt.Println(Difference(a, b).Slice(0, 10))
+	fmt.Println(SymmetricDifference(a, b).Slice(0, 10))
+	// Output:
+	// [10 22 3]
+	// [22]
+	// [10]
+	// [10 3]
+}
+
+func ExamplePersistentTree() {
+	var v1 = NewPersistent[int, string]().Set(1, "one").Set(2, "two")
+	var v2 = v1.Set(3, "three").Del(1)
+	fmt.Println(v1.Slice(0, 10))
+	fmt.Println(v2.Slice(0, 10))
+	// Output:
+	// [one two]
+	// [two three]
+}
+
+func ExampleTree_Clone() {
+	var tr = New[string, int]()
+	tr.Set("timeout", 10)
+
+	var draft = tr.Clone()
+	draft.Set("timeout", 20)
+	draft.Set("retries", 3)
+
+	fmt.Println(tr.Get("timeout"), tr.Len())
+	fmt.Println(draft.Get("timeout"), draft.Len())
+	// Output:
+	// 10 1
+	// 20 2
+}
+
+func ExampleMultiTree() {
+	var m = NewMulti[int, string]()
+	m.Add(10, "first")
+	m.Add(20, "third")
+	m.Add(10, "second")
+	for key, value := range m.All() {
+		fmt.Println(key, value)
+	}
+	// Output:
+	// 10 first
+	// 10 second
+	// 20 third
+}
+
+func ExampleAugmentedTree() {
+	// keys with the maximal value at a range
+	type best struct {
+		key, value int
+		ok         bool
+	}
+	var tr = NewAugmented(func(left best, key, value int, right best) best {
+		var b = best{key, value, true}
+		for _, x := range []best{left, right} {
+			if x.ok && x.value > b.value {
+				b = x
+			}
+		}
+		return b
+	}, best{})
+
+	for key, value := range []int{5, 9, 2, 7, 3} {
+		tr.Set(key, value)
+	}
+	fmt.Println(tr.Aggregate(2, 4))
+	fmt.Println(tr.Total())
+	// Output:
+	// {3 7 true}
+	// {1 9 true}
+}
@@ -1,11 +1,14 @@
 module github.com/logrusorgru/rbtree
 
-go 1.19
+go 1.23
+
+require (
+	github.com/stretchr/testify v1.8.0
+	golang.org/x/exp v0.0.0-20220826205824-bd9bcdd0b820
+)
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	github.com/stretchr/testify v1.8.0 // indirect
-	golang.org/x/exp v0.0.0-20220826205824-bd9bcdd0b820 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -1,14 +1,18 @@
 package rbtree
 
-import "golang.org/x/exp/constraints"
+import "iter"
 
-type TreeInterface[Key constraints.Ordered, Value any] interface {
+type TreeInterface[Key, Value any] interface {
 	Set(Key, Value) bool
 	SetNx(Key, Value) bool
 	Del(Key) bool
 	Get(Key) Value
 	GetEx(Key) (Value, bool)
 	IsExist(Key) bool
+	Floor(Key) (Key, Value, bool)
+	Ceiling(Key) (Key, Value, bool)
+	Lower(Key) (Key, Value, bool)
+	Higher(Key) (Key, Value, bool)
 	Len() int
 	Empty()
 	Move(Key, Key) bool
@@ -18,3 +22,28 @@ type TreeInterface[Key constraints.Ordered, Value any] interface {
 	Slice(Key, Key) []Value
 	SliceKeys(Key, Key) []Key
 }
+
+// ReadOnlyTree is read-only part of a Tree.
+type ReadOnlyTree[Key, Value any] interface {
+	Get(Key) Value
+	GetEx(Key) (Value, bool)
+	IsExist(Key) bool
+	Floor(Key) (Key, Value, bool)
+	Ceiling(Key) (Key, Value, bool)
+	Lower(Key) (Key, Value, bool)
+	Higher(Key) (Key, Value, bool)
+	Rank(Key) int
+	Select(int) (Key, Value, bool)
+	CountRange(Key, Key) int
+	Len() int
+	Max() (Key, Value)
+	Min() (Key, Value)
+	Walk(Key, Key, WalkFunc[Key, Value]) error
+	Slice(Key, Key) []Value
+	SliceKeys(Key, Key) []Key
+	All() iter.Seq2[Key, Value]
+	Backward() iter.Seq2[Key, Value]
+	Range(Key, Key) iter.Seq2[Key, Value]
+	Keys() iter.Seq[Key]
+	Values() iter.Seq[Value]
+}
@@ -0,0 +1,225 @@
+package rbtree
+
+import (
+	"cmp"
+	"iter"
+	"slices"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Interval is a closed interval [Lo, Hi].
+type Interval[Key any] struct {
+	Lo, Hi Key
+}
+
+// intervalValue is value of a node of the IntervalTree, the max is the
+// greatest Hi of subtree of the node
+type intervalValue[Key, Value any] struct {
+	max    Key
+	values []Value
+}
+
+// IntervalTree keeps values by closed intervals and finds the intervals
+// overlapping a given one. It's a Tree ordered by the Lo and then by the
+// Hi, every node of which keeps the greatest Hi of its subtree. Like the
+// MultiTree, it keeps many values per interval, thus intervals with the
+// same bounds coexist and are kept in order of insertion.
+type IntervalTree[Key, Value any] struct {
+	tree *Tree[Interval[Key], intervalValue[Key, Value]]
+	cmp  CompareFunc[Key]
+	len  int // number of values
+}
+
+// NewInterval creates the new empty IntervalTree.
+func NewInterval[Key constraints.Ordered,
+	Value any]() *IntervalTree[Key, Value] {
+
+	return NewIntervalFunc[Key, Value](cmp.Compare[Key])
+}
+
+// NewIntervalFunc creates the new empty IntervalTree, that uses given
+// function to compare keys. See also NewFunc.
+func NewIntervalFunc[Key, Value any](
+	cmp CompareFunc[Key]) (it *IntervalTree[Key, Value]) {
+
+	it = &IntervalTree[Key, Value]{cmp: cmp}
+	it.tree = NewFunc[Interval[Key], intervalValue[Key, Value]](
+		func(a, b Interval[Key]) int {
+			if c := cmp(a.Lo, b.Lo); c != 0 {
+				return c
+			}
+			return cmp(a.Hi, b.Hi)
+		})
+	it.tree.augment = it.augment
+	return
+}
+
+// augment updates the max of the n
+func (it *IntervalTree[Key, Value]) augment(
+	n *node[Interval[Key], intervalValue[Key, Value]]) {
+
+	var sentinel = it.tree.sentinel
+
+	n.value.max = n.key.Hi
+	if n.left != sentinel && it.cmp(n.left.value.max, n.value.max) > 0 {
+		n.value.max = n.left.value.max
+	}
+	if n.right != sentinel && it.cmp(n.right.value.max, n.value.max) > 0 {
+		n.value.max = n.right.value.max
+	}
+}
+
+// interval with ordered bounds
+func (it *IntervalTree[Key, Value]) interval(lo, hi Key) Interval[Key] {
+	if it.cmp(lo, hi) > 0 {
+		lo, hi = hi, lo
+	}
+	return Interval[Key]{Lo: lo, Hi: hi}
+}
+
+// Insert the value by the interval [lo, hi] O(logn). Existing values of
+// the same interval are kept. The bounds are swapped if the lo is greater
+// than the hi.
+func (it *IntervalTree[Key, Value]) Insert(lo, hi Key, value Value) {
+	it.tree.Upsert(it.interval(lo, hi), func(iv intervalValue[Key, Value],
+		_ bool) intervalValue[Key, Value] {
+
+		iv.values = append(iv.values, value)
+		return iv
+	})
+	it.len++
+}
+
+// Delete the first value of the interval [lo, hi], for which given
+// function returns true O(logn+k). If the function is nil, then the first
+// value is deleted. It returns false, if there is no such value.
+func (it *IntervalTree[Key, Value]) Delete(lo, hi Key,
+	match func(value Value) bool) (deleted bool) {
+
+	var i = it.interval(lo, hi)
+	it.tree.WalkMut(i, i, func(_ Interval[Key],
+		iv *intervalValue[Key, Value]) (Action, error) {
+
+		for j, value := range iv.values {
+			if match == nil || match(value) {
+				iv.values = slices.Delete(iv.values, j, j+1)
+				deleted = true
+				break
+			}
+		}
+		if len(iv.values) == 0 {
+			return Delete, nil
+		}
+		return Keep, nil
+	})
+	if deleted {
+		it.len--
+	}
+	return
+}
+
+// DeleteAll deletes all values of the interval [lo, hi] O(logn). It
+// returns number of deleted values.
+func (it *IntervalTree[Key, Value]) DeleteAll(lo, hi Key) (deleted int) {
+	var iv, _ = it.tree.LoadAndDelete(it.interval(lo, hi))
+	it.len -= len(iv.values)
+	return len(iv.values)
+}
+
+// GetAll returns all values of the interval [lo, hi] in order of
+// insertion O(logn+k). It returns nil, if there is no such interval.
+func (it *IntervalTree[Key, Value]) GetAll(lo, hi Key) []Value {
+	return slices.Clone(it.tree.Get(it.interval(lo, hi)).values)
+}
+
+// Len returns number of values O(1).
+func (it *IntervalTree[Key, Value]) Len() int {
+	return it.len
+}
+
+// LenIntervals returns number of distinct intervals O(1).
+func (it *IntervalTree[Key, Value]) LenIntervals() int {
+	return it.tree.Len()
+}
+
+// Empty makes the tree empty O(1).
+func (it *IntervalTree[Key, Value]) Empty() {
+	it.tree.Empty()
+	it.len = 0
+}
+
+// overlapping calls the yield for intervals of subtree of the n, that
+// overlap the [lo, hi], in ascending order, an interval is repeated for
+// every its value. It returns false if the yield returns false.
+func (it *IntervalTree[Key, Value]) overlapping(
+	n *node[Interval[Key], intervalValue[Key, Value]], lo, hi Key,
+	yield func(Interval[Key], Value) bool) bool {
+
+	for n != it.tree.sentinel && it.cmp(n.value.max, lo) >= 0 {
+		if !it.overlapping(n.left, lo, hi, yield) {
+			return false
+		}
+		if it.cmp(n.key.Lo, hi) > 0 {
+			return true // the n and its right subtree are after the hi
+		}
+		if it.cmp(n.key.Hi, lo) >= 0 {
+			for _, value := range n.value.values {
+				if !yield(n.key, value) {
+					return false
+				}
+			}
+		}
+		n = n.right
+	}
+	return true
+}
+
+// OverlappingSeq returns an iterator over intervals overlapping the
+// [lo, hi] and their values in ascending order of intervals. Values of
+// the same interval are yielded in order of insertion. It takes
+// O(min(n, (k+1)logn)), where k is number of the intervals. The tree
+// can't be modified inside the loop.
+func (it *IntervalTree[Key, Value]) OverlappingSeq(lo,
+	hi Key) iter.Seq2[Interval[Key], Value] {
+
+	var i = it.interval(lo, hi)
+	return func(yield func(Interval[Key], Value) bool) {
+		it.overlapping(it.tree.root, i.Lo, i.Hi, yield)
+	}
+}
+
+// Overlapping returns all intervals overlapping the [lo, hi] and their
+// values in ascending order of intervals. See also OverlappingSeq.
+func (it *IntervalTree[Key, Value]) Overlapping(lo, hi Key) (
+	intervals []Interval[Key], values []Value) {
+
+	for i, v := range it.OverlappingSeq(lo, hi) {
+		intervals = append(intervals, i)
+		values = append(values, v)
+	}
+	return
+}
+
+// Stab returns all intervals containing the point and their values in
+// ascending order of intervals.
+func (it *IntervalTree[Key, Value]) Stab(point Key) (
+	intervals []Interval[Key], values []Value) {
+
+	return it.Overlapping(point, point)
+}
+
+// All returns an iterator over all intervals and their values in
+// ascending order of intervals. An interval is repeated for every its
+// value.
+func (it *IntervalTree[Key, Value]) All() iter.Seq2[Interval[Key], Value] {
+	return func(yield func(Interval[Key], Value) bool) {
+		for i, iv := range it.tree.All() {
+			for _, value := range iv.values {
+				if !yield(i, value) {
+					return
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,216 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIntervalTree_Insert(t *testing.T) {
+
+	var it = NewInterval[int, string]()
+
+	it.Insert(1, 5, "a")
+	it.Insert(3, 8, "b")
+	it.Insert(10, 2, "c") // swapped
+	it.Insert(1, 5, "A")  // the same bounds, both are kept
+	it.Insert(5, 1, "a")
+	assert.Equal(t, 5, it.Len())
+	assert.Equal(t, 3, it.LenIntervals())
+	checkTree(t, it.tree)
+
+	assert.Equal(t, []string{"a", "A", "a"}, it.GetAll(1, 5))
+	assert.Equal(t, []string{"c"}, it.GetAll(2, 10))
+	assert.Nil(t, it.GetAll(1, 8))
+
+	var intervals, values = it.Stab(4)
+	assert.Equal(t, []Interval[int]{{1, 5}, {1, 5}, {1, 5}, {2, 10},
+		{3, 8}}, intervals)
+	assert.Equal(t, []string{"a", "A", "a", "c", "b"}, values)
+
+	assert.True(t, it.Delete(5, 1, nil))
+	assert.Equal(t, []string{"A", "a"}, it.GetAll(1, 5))
+	assert.True(t, it.Delete(1, 5, func(v string) bool { return v == "a" }))
+	assert.False(t, it.Delete(1, 5, func(v string) bool { return v == "a" }))
+	assert.False(t, it.Delete(1, 8, nil))
+	assert.Equal(t, []string{"A"}, it.GetAll(1, 5))
+	assert.True(t, it.Delete(1, 5, nil))
+	assert.Nil(t, it.GetAll(1, 5))
+	assert.Equal(t, 2, it.Len())
+	assert.Equal(t, 2, it.LenIntervals())
+	checkTree(t, it.tree)
+
+	it.Insert(3, 8, "B")
+	assert.Equal(t, 2, it.DeleteAll(8, 3))
+	assert.Zero(t, it.DeleteAll(3, 8))
+	assert.Equal(t, 1, it.Len())
+	checkTree(t, it.tree)
+
+	it.Empty()
+	assert.Zero(t, it.Len())
+	intervals, values = it.Stab(1)
+	assert.Empty(t, intervals)
+	assert.Empty(t, values)
+}
+
+func TestIntervalTree_Overlapping(t *testing.T) {
+
+	var it = NewInterval[int, string]()
+	it.Insert(1, 3, "a")
+	it.Insert(2, 6, "b")
+	it.Insert(5, 5, "c")
+	it.Insert(7, 9, "d")
+	it.Insert(8, 20, "e")
+
+	var intervals, values = it.Overlapping(4, 7)
+	assert.Equal(t, []Interval[int]{{2, 6}, {5, 5}, {7, 9}}, intervals)
+	assert.Equal(t, []string{"b", "c", "d"}, values)
+
+	intervals, values = it.Overlapping(7, 4)
+	assert.Equal(t, []string{"b", "c", "d"}, values)
+
+	intervals, values = it.Stab(3)
+	assert.Equal(t, []Interval[int]{{1, 3}, {2, 6}}, intervals)
+	assert.Equal(t, []string{"a", "b"}, values)
+
+	intervals, _ = it.Stab(21)
+	assert.Empty(t, intervals)
+	intervals, _ = it.Stab(0)
+	assert.Empty(t, intervals)
+
+	values = values[:0]
+	for _, v := range it.OverlappingSeq(0, 100) {
+		values = append(values, v)
+		if len(values) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []string{"a", "b"}, values)
+
+	values = values[:0]
+	for _, v := range it.All() {
+		values = append(values, v)
+	}
+	assert.Equal(t, []string{"a", "b", "c", "d", "e"}, values)
+}
+
+func TestIntervalTree_random(t *testing.T) {
+
+	var (
+		it     = NewInterval[int, int]()
+		oracle = make(map[Interval[int]][]int)
+		rnd    = rand.New(rand.NewSource(1))
+		size   int
+	)
+
+	var overlapping = func(lo, hi int) (values []int) {
+		for i, vs := range oracle {
+			if i.Lo <= hi && i.Hi >= lo {
+				values = append(values, vs...)
+			}
+		}
+		return
+	}
+
+	for i := 0; i < 2000; i++ {
+		var lo = rnd.Intn(300)
+		var hi = lo + rnd.Intn(50)
+		if rnd.Intn(3) == 0 {
+			var got, values = it.Overlapping(lo, hi)
+			assert.ElementsMatch(t, overlapping(lo, hi), values)
+			assert.True(t, slices.IsSortedFunc(got, it.tree.cmp))
+			continue
+		}
+		if rnd.Intn(3) == 0 && it.LenIntervals() > 0 {
+			var k, _, _ = it.tree.Select(rnd.Intn(it.LenIntervals()))
+			assert.True(t, it.Delete(k.Lo, k.Hi, nil))
+			if oracle[k] = oracle[k][1:]; len(oracle[k]) == 0 {
+				delete(oracle, k)
+			}
+			size--
+		} else {
+			it.Insert(lo, hi, i)
+			var k = Interval[int]{lo, hi}
+			oracle[k] = append(oracle[k], i)
+			size++
+		}
+		if i%100 == 0 {
+			checkTree(t, it.tree)
+		}
+	}
+	checkTree(t, it.tree)
+	assert.Equal(t, size, it.Len())
+	assert.Equal(t, len(oracle), it.LenIntervals())
+	for k, values := range oracle {
+		assert.Equal(t, values, it.GetAll(k.Lo, k.Hi))
+	}
+}
+
+func TestIntervalTree_augmented(t *testing.T) {
+
+	var it = NewInterval[int, int]()
+	for i := range 200 {
+		it.Insert(i, i+(i*7)%31, i)
+	}
+
+	// the max is kept by operations on the underlying tree
+	var c = it.tree.Clone()
+	c.DeleteRange(Interval[int]{50, 0}, Interval[int]{80, 0})
+	checkTree(t, c)
+
+	var l, r = c.Split(Interval[int]{100, 0})
+	checkTree(t, l)
+	checkTree(t, r)
+	var j, err = Join(l, r)
+	assert.NoError(t, err)
+	checkTree(t, j)
+
+	it.tree.WalkMut(Interval[int]{0, 0}, Interval[int]{300, 0},
+		func(key Interval[int], _ *intervalValue[int, int]) (Action, error) {
+			if key.Lo%3 == 0 {
+				return Delete, nil
+			}
+			return Keep, nil
+		})
+	checkTree(t, it.tree)
+
+	it.tree.PopMinN(10)
+	checkTree(t, it.tree)
+}
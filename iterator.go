@@ -0,0 +1,137 @@
+package rbtree
+
+// Iterator is a bidirectional cursor over a Tree. Unlike the Walk, the
+// Iterator can be paused, resumed and interleaved with other iterators.
+//
+//	var it = tr.Iter()
+//	for ok := it.SeekFirst(); ok; ok = it.Next() {
+//	    fmt.Println(it.Key(), it.Value())
+//	}
+//
+// A new Iterator is not positioned, use one of the Seek methods first.
+// The Tree shouldn't be modified while the Iterator is in use, a
+// modification invalidates the Iterator and it should be positioned again.
+type Iterator[Key, Value any] struct {
+	tree *Tree[Key, Value]
+	node *node[Key, Value]
+}
+
+// Iter returns new Iterator of the Tree.
+func (t *Tree[Key, Value]) Iter() *Iterator[Key, Value] {
+	return &Iterator[Key, Value]{
+		tree: t,
+		node: t.sentinel,
+	}
+}
+
+// Valid returns true if the Iterator points to an element.
+func (i *Iterator[Key, Value]) Valid() bool {
+	return i.node != i.tree.sentinel
+}
+
+// Seek moves the Iterator to the smallest key greater than or equal to
+// the given one O(logn). It returns false, if there is no such key.
+func (i *Iterator[Key, Value]) Seek(key Key) bool {
+	i.node = i.tree.ceilingNode(key)
+	return i.Valid()
+}
+
+// SeekFirst moves the Iterator to the minimum key O(logn). It returns
+// false, if the Tree is empty.
+func (i *Iterator[Key, Value]) SeekFirst() bool {
+	i.node = i.tree.minimum(i.tree.root)
+	return i.Valid()
+}
+
+// SeekLast moves the Iterator to the maximum key O(logn). It returns
+// false, if the Tree is empty.
+func (i *Iterator[Key, Value]) SeekLast() bool {
+	i.node = i.tree.maximum(i.tree.root)
+	return i.Valid()
+}
+
+// Next moves the Iterator to the next key. It returns false, if there
+// is no next key. Amortized O(1).
+func (i *Iterator[Key, Value]) Next() bool {
+	if i.node == i.tree.sentinel {
+		return false
+	}
+	i.node = i.tree.successor(i.node)
+	return i.Valid()
+}
+
+// Prev moves the Iterator to the previous key. It returns false, if there
+// is no previous key. Amortized O(1).
+func (i *Iterator[Key, Value]) Prev() bool {
+	if i.node == i.tree.sentinel {
+		return false
+	}
+	i.node = i.tree.predecessor(i.node)
+	return i.Valid()
+}
+
+// Key of current element. It returns zero key, if the Iterator
+// is not valid.
+func (i *Iterator[Key, Value]) Key() Key {
+	return i.node.key
+}
+
+// Value of current element. It returns zero value, if the Iterator
+// is not valid.
+func (i *Iterator[Key, Value]) Value() Value {
+	return i.node.value
+}
+
+func (t *Tree[Key, Value]) minimum(x *node[Key, Value]) *node[Key, Value] {
+	for x.left != t.sentinel {
+		x = x.left
+	}
+	return x
+}
+
+func (t *Tree[Key, Value]) maximum(x *node[Key, Value]) *node[Key, Value] {
+	for x.right != t.sentinel {
+		x = x.right
+	}
+	return x
+}
+
+func (t *Tree[Key, Value]) successor(x *node[Key, Value]) *node[Key, Value] {
+
+	if x.right != t.sentinel {
+		return t.minimum(x.right)
+	}
+	if t.stale && x.gen != t.gen.Load() {
+		return t.higherNode(x.key) // the parent can be wrong O(logn)
+	}
+
+	var y = x.parent
+	for y != nil && x == y.right {
+		x, y = y, y.parent
+	}
+
+	if y == nil {
+		return t.sentinel
+	}
+	return y
+}
+
+func (t *Tree[Key, Value]) predecessor(x *node[Key, Value]) *node[Key, Value] {
+
+	if x.left != t.sentinel {
+		return t.maximum(x.left)
+	}
+	if t.stale && x.gen != t.gen.Load() {
+		return t.lowerNode(x.key) // the parent can be wrong O(logn)
+	}
+
+	var y = x.parent
+	for y != nil && x == y.left {
+		x, y = y, y.parent
+	}
+
+	if y == nil {
+		return t.sentinel
+	}
+	return y
+}
@@ -0,0 +1,165 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIterator(t *testing.T) {
+
+	var tr = New[int, string]()
+
+	var it = tr.Iter()
+	assert.False(t, it.Valid())
+	assert.False(t, it.SeekFirst())
+	assert.False(t, it.SeekLast())
+	assert.False(t, it.Seek(0))
+	assert.False(t, it.Next())
+	assert.False(t, it.Prev())
+	assert.Zero(t, it.Key())
+	assert.Zero(t, it.Value())
+
+	tr.Set(1, "one")
+	tr.Set(3, "three")
+	tr.Set(5, "five")
+
+	assert.True(t, it.SeekFirst())
+	assert.Equal(t, 1, it.Key())
+	assert.Equal(t, "one", it.Value())
+	assert.True(t, it.Next())
+	assert.Equal(t, 3, it.Key())
+	assert.True(t, it.Next())
+	assert.Equal(t, 5, it.Key())
+	assert.False(t, it.Next())
+	assert.False(t, it.Valid())
+	assert.False(t, it.Prev())
+
+	assert.True(t, it.SeekLast())
+	assert.Equal(t, 5, it.Key())
+	assert.True(t, it.Prev())
+	assert.Equal(t, 3, it.Key())
+	assert.True(t, it.Prev())
+	assert.Equal(t, 1, it.Key())
+	assert.False(t, it.Prev())
+
+	assert.True(t, it.Seek(3))
+	assert.Equal(t, 3, it.Key())
+	assert.True(t, it.Seek(2))
+	assert.Equal(t, 3, it.Key())
+	assert.True(t, it.Seek(math.MinInt))
+	assert.Equal(t, 1, it.Key())
+	assert.False(t, it.Seek(6))
+}
+
+func Test_randomIterator(t *testing.T) {
+
+	var (
+		tr   = New[int, int]()
+		keys []int
+	)
+
+	for i := 0; i < Count; i++ {
+		var k = rand.Intn(Count * 10)
+		if tr.Set(k, -k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Ints(keys)
+
+	var (
+		it = tr.Iter()
+		i  int
+	)
+	for ok := it.SeekFirst(); ok; ok = it.Next() {
+		assert.Equal(t, keys[i], it.Key())
+		assert.Equal(t, -keys[i], it.Value())
+		i++
+	}
+	assert.Equal(t, len(keys), i)
+
+	for ok := it.SeekLast(); ok; ok = it.Prev() {
+		i--
+		assert.Equal(t, keys[i], it.Key())
+	}
+	assert.Zero(t, i)
+
+	for j := 0; j < 100; j++ {
+		var (
+			k = rand.Intn(Count * 10)
+			n = sort.SearchInts(keys, k)
+		)
+		if !it.Seek(k) {
+			assert.Equal(t, len(keys), n)
+			continue
+		}
+		assert.Equal(t, keys[n], it.Key())
+	}
+}
+
+func Test_mergeJoinIterators(t *testing.T) {
+
+	var a, b = New[int, string](), New[int, string]()
+	for i := 0; i < 20; i += 2 {
+		a.Set(i, "a")
+	}
+	for i := 0; i < 20; i += 3 {
+		b.Set(i, "b")
+	}
+
+	var (
+		ai, bi   = a.Iter(), b.Iter()
+		aok, bok = ai.SeekFirst(), bi.SeekFirst()
+		common   []int
+	)
+	for aok && bok {
+		switch {
+		case ai.Key() < bi.Key():
+			aok = ai.Next()
+		case ai.Key() > bi.Key():
+			bok = bi.Next()
+		default:
+			common = append(common, ai.Key())
+			aok, bok = ai.Next(), bi.Next()
+		}
+	}
+	assert.Equal(t, []int{0, 6, 12, 18}, common)
+}
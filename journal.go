@@ -0,0 +1,91 @@
+package rbtree
+
+// journal keeps functions, that undo changes of a Tree in reverse order
+type journal struct {
+	undo []func()
+}
+
+// record given undo function, if the Tree keeps a journal
+func (t *Tree[Key, Value]) record(undo func()) {
+	if t.journal != nil {
+		t.journal.undo = append(t.journal.undo, undo)
+	}
+}
+
+// recordSet records undo of the Set of the key, that replaces the old
+// value or adds new key
+func (t *Tree[Key, Value]) recordSet(key Key, old Value, exists bool) {
+	if t.journal == nil {
+		return
+	}
+	if exists {
+		t.record(func() { t.insertNode(key, old, true) })
+	} else {
+		t.record(func() { t.Del(key) })
+	}
+}
+
+// recordRoot records undo of a change, that drops all nodes of the Tree,
+// it should be called before the change. The dropped nodes are not changed
+// anymore, and the undo takes them back O(1).
+func (t *Tree[Key, Value]) recordRoot() {
+	if t.journal == nil {
+		return
+	}
+	var root, n, stale = t.root, t.len, t.stale
+	t.record(func() {
+		t.reset(root, n)
+		t.stale = stale
+	})
+}
+
+// recordCut records undo of a change, that cuts the m out of the Tree,
+// it should be called after the change. All keys of the m are between
+// two neighbour keys of the Tree. The m is not changed anymore, and the
+// undo joins it back O(logn).
+func (t *Tree[Key, Value]) recordCut(m *node[Key, Value]) {
+	if t.journal == nil || m == t.sentinel {
+		return
+	}
+	var n = t.len + m.size
+	t.record(func() {
+		var l, lh, r, _ = t.split(t.ownRoot(), t.blackHeight(t.root),
+			t.minimum(m).key, false)
+		l = t.concat(l, m, lh)
+		t.reset(t.concat(l, r, t.blackHeight(l)), n)
+	})
+}
+
+// recordSnapshot records undo of a change, that moves nodes of the Tree to
+// other trees, it should be called before the change. The undo restores
+// whole tree. It takes O(1), but the nodes become shared, see Clone.
+func (t *Tree[Key, Value]) recordSnapshot() {
+	if t.journal == nil {
+		return
+	}
+	var snap = t.Clone()
+	t.record(func() {
+		t.root, t.len, t.min, t.max = snap.root, snap.len, snap.min, snap.max
+		t.stale = snap.stale
+		t.gen.Store(snap.gen.Load()) // snap is dropped, its nodes are free
+	})
+}
+
+// begin starts the journal
+func (t *Tree[Key, Value]) begin() {
+	t.journal = new(journal)
+}
+
+// commit drops the journal
+func (t *Tree[Key, Value]) commit() {
+	t.journal = nil
+}
+
+// rollback undoes all changes since the begin and drops the journal
+func (t *Tree[Key, Value]) rollback() {
+	var j = t.journal
+	t.journal = nil
+	for i := len(j.undo) - 1; i >= 0; i-- {
+		j.undo[i]()
+	}
+}
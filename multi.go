@@ -0,0 +1,141 @@
+package rbtree
+
+import (
+	"iter"
+	"slices"
+
+	"golang.org/x/exp/constraints"
+)
+
+// MultiTree is a RB-Tree, that keeps many values per key. Values of the
+// same key are kept in order of insertion. It's a Tree of slices of
+// values, thus a key is stored once for all its values.
+type MultiTree[Key, Value any] struct {
+	tree *Tree[Key, []Value]
+	len  int // number of values
+}
+
+// NewMulti creates the new empty MultiTree.
+func NewMulti[Key constraints.Ordered, Value any]() *MultiTree[Key, Value] {
+	return &MultiTree[Key, Value]{tree: New[Key, []Value]()}
+}
+
+// NewMultiFunc creates the new empty MultiTree, that uses given function
+// to compare keys. See also NewFunc.
+func NewMultiFunc[Key, Value any](
+	cmp CompareFunc[Key]) *MultiTree[Key, Value] {
+
+	return &MultiTree[Key, Value]{tree: NewFunc[Key, []Value](cmp)}
+}
+
+// Add the value to the key O(logn). Existing values of the key are kept.
+func (m *MultiTree[Key, Value]) Add(key Key, value Value) {
+	m.tree.Upsert(key, func(values []Value, _ bool) []Value {
+		return append(values, value)
+	})
+	m.len++
+}
+
+// GetAll returns all values of the key in order of insertion O(logn+k).
+// It returns nil, if the key doesn't exist.
+func (m *MultiTree[Key, Value]) GetAll(key Key) []Value {
+	return slices.Clone(m.tree.Get(key))
+}
+
+// Count returns number of values of the key O(logn).
+func (m *MultiTree[Key, Value]) Count(key Key) int {
+	return len(m.tree.Get(key))
+}
+
+// IsExist O(logn)
+func (m *MultiTree[Key, Value]) IsExist(key Key) bool {
+	return m.tree.IsExist(key)
+}
+
+// DelOne deletes the first value of the key, for which given function
+// returns true O(logn+k). If the function is nil, then the first value is
+// deleted. It returns false, if there is no such value.
+func (m *MultiTree[Key, Value]) DelOne(key Key,
+	match func(value Value) bool) (deleted bool) {
+
+	m.tree.WalkMut(key, key, func(_ Key, values *[]Value) (Action, error) {
+		for i, value := range *values {
+			if match == nil || match(value) {
+				*values = slices.Delete(*values, i, i+1)
+				deleted = true
+				break
+			}
+		}
+		if len(*values) == 0 {
+			return Delete, nil
+		}
+		return Keep, nil
+	})
+	if deleted {
+		m.len--
+	}
+	return
+}
+
+// DelAll deletes all values of the key O(logn). It returns number of
+// deleted values.
+func (m *MultiTree[Key, Value]) DelAll(key Key) (deleted int) {
+	var values, _ = m.tree.LoadAndDelete(key)
+	m.len -= len(values)
+	return len(values)
+}
+
+// Len returns number of values O(1).
+func (m *MultiTree[Key, Value]) Len() int {
+	return m.len
+}
+
+// LenKeys returns number of distinct keys O(1).
+func (m *MultiTree[Key, Value]) LenKeys() int {
+	return m.tree.Len()
+}
+
+// Empty makes the tree empty O(1).
+func (m *MultiTree[Key, Value]) Empty() {
+	m.tree.Empty()
+	m.len = 0
+}
+
+// Walk on the MultiTree like the Tree.Walk does. The WalkFunc is called
+// for every value, values of the same key are walked in order of
+// insertion. The MultiTree shouldn't be modified inside the WalkFunc.
+func (m *MultiTree[Key, Value]) Walk(from, to Key,
+	walkFunc WalkFunc[Key, Value]) error {
+
+	return m.tree.Walk(from, to, func(key Key, values []Value) (err error) {
+		for _, value := range values {
+			if err = walkFunc(key, value); err != nil {
+				return
+			}
+		}
+		return
+	})
+}
+
+// Slice returns all values at given range if any.
+func (m *MultiTree[Key, Value]) Slice(from, to Key) (vals []Value) {
+	m.Walk(from, to, func(_ Key, value Value) error {
+		vals = append(vals, value)
+		return nil
+	})
+	return
+}
+
+// All returns an iterator over all key-value pairs of the MultiTree in
+// ascending order of keys. A key is repeated for every its value.
+func (m *MultiTree[Key, Value]) All() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		for key, values := range m.tree.All() {
+			for _, value := range values {
+				if !yield(key, value) {
+					return
+				}
+			}
+		}
+	}
+}
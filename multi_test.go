@@ -0,0 +1,101 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultiTree(t *testing.T) {
+
+	var m = NewMulti[int, string]()
+	m.Add(2, "b1")
+	m.Add(1, "a1")
+	m.Add(2, "b2")
+	m.Add(3, "c1")
+	m.Add(2, "b3")
+
+	assert.Equal(t, 5, m.Len())
+	assert.Equal(t, 3, m.LenKeys())
+	assert.Equal(t, 3, m.Count(2))
+	assert.Equal(t, 0, m.Count(4))
+	assert.True(t, m.IsExist(1))
+	assert.False(t, m.IsExist(4))
+	assert.Equal(t, []string{"b1", "b2", "b3"}, m.GetAll(2))
+	assert.Nil(t, m.GetAll(4))
+	assert.Equal(t, []string{"a1", "b1", "b2", "b3", "c1"}, m.Slice(0, 10))
+	assert.Equal(t, []string{"c1", "b1", "b2", "b3", "a1"}, m.Slice(10, 0))
+
+	// returned slice is a copy
+	m.GetAll(2)[0] = "x"
+	assert.Equal(t, "b1", m.GetAll(2)[0])
+
+	var keys []int
+	var values []string
+	for key, value := range m.All() {
+		keys, values = append(keys, key), append(values, value)
+		if len(keys) == 3 {
+			break
+		}
+	}
+	assert.Equal(t, []int{1, 2, 2}, keys)
+	assert.Equal(t, []string{"a1", "b1", "b2"}, values)
+
+	var err = m.Walk(0, 10, func(int, string) error { return ErrStop })
+	assert.ErrorIs(t, err, ErrStop)
+
+	assert.True(t, m.DelOne(2, func(v string) bool { return v == "b2" }))
+	assert.False(t, m.DelOne(2, func(v string) bool { return v == "b2" }))
+	assert.Equal(t, []string{"b1", "b3"}, m.GetAll(2))
+	assert.True(t, m.DelOne(2, nil))
+	assert.Equal(t, []string{"b3"}, m.GetAll(2))
+	assert.True(t, m.DelOne(1, nil))
+	assert.False(t, m.IsExist(1))
+	assert.False(t, m.DelOne(1, nil))
+	assert.Equal(t, 2, m.Len())
+	checkTree(t, m.tree)
+
+	assert.Equal(t, 1, m.DelAll(2))
+	assert.Equal(t, 0, m.DelAll(2))
+	assert.Equal(t, 1, m.Len())
+	assert.Equal(t, 1, m.LenKeys())
+
+	m.Empty()
+	assert.Equal(t, 0, m.Len())
+	assert.Equal(t, 0, m.LenKeys())
+}
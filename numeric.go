@@ -0,0 +1,102 @@
+package rbtree
+
+import (
+	"cmp"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Number is a numeric type of values of the NumericTree.
+type Number interface {
+	constraints.Integer | constraints.Float
+}
+
+// Stats of values at a range of keys. The Min and the Max are zero, if
+// the Count is zero.
+type Stats[Value Number] struct {
+	Count    int
+	Sum      Value
+	Min, Max Value
+}
+
+// add the s to the x
+func (x *Stats[Value]) add(s Stats[Value]) {
+	if s.Count == 0 {
+		return
+	}
+	x.Count += s.Count
+	x.Sum += s.Sum
+	x.Min = min(x.Min, s.Min)
+	x.Max = max(x.Max, s.Max)
+}
+
+// combineStats is the CombineFunc of the NumericTree
+func combineStats[Key any, Value Number](left Stats[Value], _ Key,
+	value Value, right Stats[Value]) (s Stats[Value]) {
+
+	s = Stats[Value]{Count: 1, Sum: value, Min: value, Max: value}
+	s.add(left)
+	s.add(right)
+	return
+}
+
+// NumericTree is an AugmentedTree of numeric values, that keeps Stats of
+// every subtree. Sum, minimal and maximal value of any range of keys are
+// returned in O(logn) without walking the range. A sum of floats depends
+// on order of additions, thus it can differ from the sum of a Walk in
+// last digits.
+type NumericTree[Key any, Value Number] struct {
+	*AugmentedTree[Key, Value, Stats[Value]]
+}
+
+// NewNumeric creates the new empty NumericTree.
+func NewNumeric[Key constraints.Ordered,
+	Value Number]() (nt *NumericTree[Key, Value]) {
+
+	nt = NewNumericFunc[Key, Value](cmp.Compare[Key])
+	nt.tree.ordered = newOrdered[Key, augmentedValue[Value, Stats[Value]]]()
+	return
+}
+
+// NewNumericFunc creates the new empty NumericTree, that uses given
+// function to compare keys. See also NewFunc.
+func NewNumericFunc[Key any, Value Number](
+	cmp CompareFunc[Key]) *NumericTree[Key, Value] {
+
+	return &NumericTree[Key, Value]{NewAugmentedFunc(cmp,
+		combineStats[Key, Value], Stats[Value]{})}
+}
+
+// Clone returns an independent copy of the tree O(1), see Tree.Clone.
+func (nt *NumericTree[Key, Value]) Clone() *NumericTree[Key, Value] {
+	return &NumericTree[Key, Value]{nt.AugmentedTree.Clone()}
+}
+
+// SumRange returns sum of values at given range O(logn). The range is
+// inclusive, and the from can be greater than the to.
+func (nt *NumericTree[Key, Value]) SumRange(from, to Key) Value {
+	return nt.Aggregate(from, to).Sum
+}
+
+// MinValueRange returns minimal value at given range O(logn). It returns
+// false, if there are no keys at the range.
+func (nt *NumericTree[Key, Value]) MinValueRange(from, to Key) (Value,
+	bool) {
+
+	var s = nt.Aggregate(from, to)
+	return s.Min, s.Count > 0
+}
+
+// MaxValueRange returns maximal value at given range O(logn). It returns
+// false, if there are no keys at the range.
+func (nt *NumericTree[Key, Value]) MaxValueRange(from, to Key) (Value,
+	bool) {
+
+	var s = nt.Aggregate(from, to)
+	return s.Max, s.Count > 0
+}
+
+// CountRange returns number of keys at given range O(logn).
+func (nt *NumericTree[Key, Value]) CountRange(from, to Key) int {
+	return nt.Aggregate(from, to).Count
+}
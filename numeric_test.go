@@ -0,0 +1,135 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumericTree(t *testing.T) {
+
+	var nt = NewNumeric[int, float64]()
+
+	var _, ok = nt.MinValueRange(0, 100)
+	assert.False(t, ok)
+	_, ok = nt.MaxValueRange(0, 100)
+	assert.False(t, ok)
+	assert.Zero(t, nt.SumRange(0, 100))
+
+	for i, v := range []float64{3, -1, 4, 1, -5, 9, 2, 6} {
+		nt.Set(i*10, v)
+	}
+	checkTree(t, nt.tree)
+
+	assert.Equal(t, 19.0, nt.SumRange(0, 70))
+	assert.Equal(t, 8, nt.CountRange(0, 70))
+	assert.Equal(t, 0.0, nt.SumRange(15, 45))
+	assert.Equal(t, 0.0, nt.SumRange(45, 15))
+	assert.Equal(t, 3, nt.CountRange(45, 15))
+
+	var v float64
+	v, ok = nt.MinValueRange(5, 35)
+	assert.True(t, ok)
+	assert.Equal(t, -1.0, v)
+	v, ok = nt.MaxValueRange(5, 35)
+	assert.True(t, ok)
+	assert.Equal(t, 4.0, v)
+	v, ok = nt.MaxValueRange(50, 50)
+	assert.True(t, ok)
+	assert.Equal(t, 9.0, v)
+	_, ok = nt.MaxValueRange(51, 59)
+	assert.False(t, ok)
+
+	var c = nt.Clone()
+	c.Del(50)
+	v, _ = c.MaxValueRange(0, 70)
+	assert.Equal(t, 6.0, v)
+	v, _ = nt.MaxValueRange(0, 70)
+	assert.Equal(t, 9.0, v)
+	checkTree(t, c.tree)
+}
+
+func TestNumericTree_random(t *testing.T) {
+
+	var (
+		nt     = NewNumeric[int, int]()
+		oracle = make(map[int]int)
+		rnd    = rand.New(rand.NewSource(1))
+	)
+
+	for i := 0; i < 3000; i++ {
+		var key = rnd.Intn(1000)
+		switch rnd.Intn(4) {
+		case 0:
+			var (
+				from, to      = min(key, rnd.Intn(1000)), max(key, 500)
+				want          Stats[int]
+				first         = true
+				gotMin, minOk = nt.MinValueRange(from, to)
+				gotMax, maxOk = nt.MaxValueRange(from, to)
+			)
+			for k, v := range oracle {
+				if k < from || k > to {
+					continue
+				}
+				want.Count++
+				want.Sum += v
+				if first {
+					want.Min, want.Max, first = v, v, false
+				}
+				want.Min, want.Max = min(want.Min, v), max(want.Max, v)
+			}
+			assert.Equal(t, want, nt.Aggregate(from, to))
+			assert.Equal(t, want.Sum, nt.SumRange(from, to))
+			assert.Equal(t, want.Count, nt.CountRange(from, to))
+			assert.Equal(t, want.Count > 0, minOk)
+			assert.Equal(t, want.Count > 0, maxOk)
+			assert.Equal(t, want.Min, gotMin)
+			assert.Equal(t, want.Max, gotMax)
+		case 1:
+			nt.Del(key)
+			delete(oracle, key)
+		default:
+			var v = rnd.Intn(2000) - 1000
+			nt.Set(key, v)
+			oracle[key] = v
+		}
+	}
+	checkTree(t, nt.tree)
+}
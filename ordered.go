@@ -0,0 +1,130 @@
+package rbtree
+
+import (
+	"cmp"
+
+	"golang.org/x/exp/constraints"
+)
+
+// ordered keeps versions of the hottest methods of the Tree for ordered
+// keys. They compare keys by the == and the cmp.Less, that are inlined,
+// instead of calling the comparison function for every node. A Tree created
+// by the New uses them, thus only one indirect call is made per operation.
+type ordered[Key, Value any] struct {
+	findNode func(t *Tree[Key, Value], key Key) *node[Key, Value]
+	descend  func(t *Tree[Key, Value], key Key, d int) (current,
+		parent *node[Key, Value], c int)
+	walkLeft, walkRight func(t *Tree[Key, Value], n *node[Key, Value],
+		from, to Key, walkFunc WalkFunc[Key, Value]) error
+}
+
+func newOrdered[Key constraints.Ordered, Value any]() *ordered[Key, Value] {
+	return &ordered[Key, Value]{
+		findNode:  findNodeOrdered[Key, Value],
+		descend:   descendOrdered[Key, Value],
+		walkLeft:  walkLeftOrdered[Key, Value],
+		walkRight: walkRightOrdered[Key, Value],
+	}
+}
+
+// keysEqual reports whether the keys are equal, NaN is equal to NaN, the same
+// way as the cmp.Compare does
+func keysEqual[Key constraints.Ordered](a, b Key) bool {
+	return a == b || a != a && b != b
+}
+
+// findNodeOrdered is the Tree.findNode for ordered keys
+func findNodeOrdered[Key constraints.Ordered, Value any](t *Tree[Key, Value],
+	key Key) *node[Key, Value] {
+
+	var current = t.root
+
+	for current != t.sentinel {
+		if keysEqual(key, current.key) {
+			return current
+		}
+		if cmp.Less(key, current.key) {
+			current = current.left
+		} else {
+			current = current.right
+		}
+	}
+
+	return current // root sentinel
+}
+
+// descendOrdered is the Tree.descend for ordered keys
+func descendOrdered[Key constraints.Ordered, Value any](t *Tree[Key, Value],
+	key Key, d int) (current, parent *node[Key, Value], c int) {
+
+	var gen = t.gen.Load()
+	current = t.root
+
+	for current != t.sentinel {
+		if current.gen != gen {
+			current = t.copyNode(current, parent, c < 0)
+		}
+		if keysEqual(key, current.key) {
+			return current, parent, 0
+		}
+		current.size += d
+		parent = current
+		if cmp.Less(key, current.key) {
+			current, c = current.left, -1
+		} else {
+			current, c = current.right, 1
+		}
+	}
+
+	return
+}
+
+// walkLeftOrdered is the Tree.walkLeft for ordered keys
+func walkLeftOrdered[Key constraints.Ordered, Value any](t *Tree[Key, Value],
+	n *node[Key, Value], from, to Key,
+	walkFunc WalkFunc[Key, Value]) (err error) {
+
+	if cmp.Less(from, n.key) && n.left != t.sentinel {
+		err = walkLeftOrdered(t, n.left, from, to, walkFunc)
+		if err != nil {
+			return
+		}
+	}
+
+	if !cmp.Less(n.key, from) && !cmp.Less(to, n.key) {
+		if err = walkFunc(n.key, n.value); err != nil {
+			return
+		}
+	}
+
+	if cmp.Less(n.key, to) && n.right != t.sentinel {
+		return walkLeftOrdered(t, n.right, from, to, walkFunc)
+	}
+
+	return // nil
+}
+
+// walkRightOrdered is the Tree.walkRight for ordered keys
+func walkRightOrdered[Key constraints.Ordered, Value any](t *Tree[Key, Value],
+	n *node[Key, Value], from, to Key,
+	walkFunc WalkFunc[Key, Value]) (err error) {
+
+	if cmp.Less(n.key, from) && n.right != t.sentinel {
+		err = walkRightOrdered(t, n.right, from, to, walkFunc)
+		if err != nil {
+			return
+		}
+	}
+
+	if !cmp.Less(from, n.key) && !cmp.Less(n.key, to) {
+		if err = walkFunc(n.key, n.value); err != nil {
+			return
+		}
+	}
+
+	if cmp.Less(to, n.key) && n.left != t.sentinel {
+		return walkRightOrdered(t, n.left, from, to, walkFunc)
+	}
+
+	return // nil
+}
@@ -0,0 +1,485 @@
+package rbtree
+
+import (
+	"cmp"
+	"iter"
+
+	"golang.org/x/exp/constraints"
+)
+
+// pnode is node of a PersistentTree. Nodes of a PersistentTree are never
+// changed after the tree is created, thus different versions of the tree
+// share them. There is no parent pointer and no sentinel, leaves are nil.
+type pnode[Key, Value any] struct {
+	left  *pnode[Key, Value]
+	right *pnode[Key, Value]
+	size  int // number of nodes of the subtree
+	color color
+	key   Key
+	value Value
+}
+
+func (n *pnode[Key, Value]) isRed() bool {
+	return n != nil && n.color == red
+}
+
+func (n *pnode[Key, Value]) count() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// clone returns copy of the n, that can be changed
+func (n *pnode[Key, Value]) clone() *pnode[Key, Value] {
+	var c = *n
+	return &c
+}
+
+// PersistentTree is an immutable left-leaning RB-Tree. The Set and the
+// Del return new version of the tree copying only O(logn) nodes of the
+// path, all other nodes are shared with the previous version. Thus, every
+// version is a consistent snapshot, that can be read by any number of
+// goroutines without locks.
+type PersistentTree[Key, Value any] struct {
+	root *pnode[Key, Value]
+	len  int
+	cmp  CompareFunc[Key]
+}
+
+// NewPersistent creates the new empty PersistentTree.
+func NewPersistent[Key constraints.Ordered,
+	Value any]() *PersistentTree[Key, Value] {
+
+	return NewPersistentFunc[Key, Value](cmp.Compare[Key])
+}
+
+// NewPersistentFunc creates the new empty PersistentTree, that uses given
+// function to compare keys. See also NewFunc.
+func NewPersistentFunc[Key, Value any](
+	cmp CompareFunc[Key]) *PersistentTree[Key, Value] {
+
+	return &PersistentTree[Key, Value]{cmp: cmp}
+}
+
+// the nodes below are always new copies, that can be changed
+
+func (p *PersistentTree[Key, Value]) rotateLeft(
+	h *pnode[Key, Value]) *pnode[Key, Value] {
+
+	var x = h.right.clone()
+	h.right = x.left
+	x.left = h
+	x.color, h.color = h.color, red
+	x.size = h.size
+	h.size = h.left.count() + h.right.count() + 1
+	return x
+}
+
+func (p *PersistentTree[Key, Value]) rotateRight(
+	h *pnode[Key, Value]) *pnode[Key, Value] {
+
+	var x = h.left.clone()
+	h.left = x.right
+	x.right = h
+	x.color, h.color = h.color, red
+	x.size = h.size
+	h.size = h.left.count() + h.right.count() + 1
+	return x
+}
+
+func (p *PersistentTree[Key, Value]) flipColors(h *pnode[Key, Value]) {
+	h.left, h.right = h.left.clone(), h.right.clone()
+	h.color = !h.color
+	h.left.color = !h.left.color
+	h.right.color = !h.right.color
+}
+
+// fixUp restores the properties on the way up
+func (p *PersistentTree[Key, Value]) fixUp(
+	h *pnode[Key, Value]) *pnode[Key, Value] {
+
+	if h.right.isRed() && !h.left.isRed() {
+		h = p.rotateLeft(h)
+	}
+	if h.left.isRed() && h.left.left.isRed() {
+		h = p.rotateRight(h)
+	}
+	if h.left.isRed() && h.right.isRed() {
+		p.flipColors(h)
+	}
+	h.size = h.left.count() + h.right.count() + 1
+	return h
+}
+
+func (p *PersistentTree[Key, Value]) moveRedLeft(
+	h *pnode[Key, Value]) *pnode[Key, Value] {
+
+	p.flipColors(h)
+	if h.right.left.isRed() {
+		h.right = p.rotateRight(h.right)
+		h = p.rotateLeft(h)
+		p.flipColors(h)
+	}
+	return h
+}
+
+func (p *PersistentTree[Key, Value]) moveRedRight(
+	h *pnode[Key, Value]) *pnode[Key, Value] {
+
+	p.flipColors(h)
+	if h.left.left.isRed() {
+		h = p.rotateRight(h)
+		p.flipColors(h)
+	}
+	return h
+}
+
+func (p *PersistentTree[Key, Value]) insert(h *pnode[Key, Value], key Key,
+	value Value) (_ *pnode[Key, Value], added bool) {
+
+	if h == nil {
+		return &pnode[Key, Value]{
+			size:  1,
+			color: red,
+			key:   key,
+			value: value,
+		}, true
+	}
+
+	h = h.clone()
+	switch c := p.cmp(key, h.key); {
+	case c < 0:
+		h.left, added = p.insert(h.left, key, value)
+	case c > 0:
+		h.right, added = p.insert(h.right, key, value)
+	default:
+		h.value = value
+	}
+	return p.fixUp(h), added
+}
+
+func (p *PersistentTree[Key, Value]) deleteMin(
+	h *pnode[Key, Value]) *pnode[Key, Value] {
+
+	if h.left == nil {
+		return nil
+	}
+	h = h.clone()
+	if !h.left.isRed() && !h.left.left.isRed() {
+		h = p.moveRedLeft(h)
+	}
+	h.left = p.deleteMin(h.left)
+	return p.fixUp(h)
+}
+
+// delete the key, that must exist in the subtree of the h
+func (p *PersistentTree[Key, Value]) delete(h *pnode[Key, Value],
+	key Key) *pnode[Key, Value] {
+
+	h = h.clone()
+	if p.cmp(key, h.key) < 0 {
+		if !h.left.isRed() && !h.left.left.isRed() {
+			h = p.moveRedLeft(h)
+		}
+		h.left = p.delete(h.left, key)
+		return p.fixUp(h)
+	}
+
+	if h.left.isRed() {
+		h = p.rotateRight(h)
+	}
+	if p.cmp(key, h.key) == 0 && h.right == nil {
+		return nil
+	}
+	if !h.right.isRed() && !h.right.left.isRed() {
+		h = p.moveRedRight(h)
+	}
+	if p.cmp(key, h.key) == 0 {
+		var min = h.right
+		for min.left != nil {
+			min = min.left
+		}
+		h.key, h.value = min.key, min.value
+		h.right = p.deleteMin(h.right)
+	} else {
+		h.right = p.delete(h.right, key)
+	}
+	return p.fixUp(h)
+}
+
+func (p *PersistentTree[Key, Value]) findNode(key Key) *pnode[Key, Value] {
+
+	var current = p.root
+
+	for current != nil {
+		switch c := p.cmp(key, current.key); {
+		case c < 0:
+			current = current.left
+		case c > 0:
+			current = current.right
+		default:
+			return current
+		}
+	}
+
+	return nil
+}
+
+// Set returns new version of the tree with given key and value O(logn).
+// The PersistentTree is not changed.
+func (p *PersistentTree[Key, Value]) Set(key Key,
+	value Value) *PersistentTree[Key, Value] {
+
+	var root, added = p.insert(p.root, key, value)
+	root.color = black // root is new copy
+
+	var n = p.len
+	if added {
+		n++
+	}
+	return &PersistentTree[Key, Value]{root: root, len: n, cmp: p.cmp}
+}
+
+// Del returns new version of the tree without given key O(logn). If the
+// key doesn't exist, then the same PersistentTree returned.
+func (p *PersistentTree[Key, Value]) Del(key Key) *PersistentTree[Key, Value] {
+
+	if p.findNode(key) == nil {
+		return p
+	}
+
+	var root = p.root.clone()
+	if !root.left.isRed() && !root.right.isRed() {
+		root.color = red
+	}
+	if root = p.delete(root, key); root != nil {
+		root.color = black // root is new copy
+	}
+	return &PersistentTree[Key, Value]{root: root, len: p.len - 1, cmp: p.cmp}
+}
+
+// lowerNode returns node with the greatest key less than given one, or
+// less than or equal to the key, if the inclusive is true
+func (p *PersistentTree[Key, Value]) lowerNode(key Key,
+	inclusive bool) (found *pnode[Key, Value]) {
+
+	for current := p.root; current != nil; {
+		switch c := p.cmp(key, current.key); {
+		case c == 0 && inclusive:
+			return current
+		case c <= 0:
+			current = current.left
+		default:
+			found, current = current, current.right
+		}
+	}
+	return
+}
+
+// higherNode returns node with the smallest key greater than given one, or
+// greater than or equal to the key, if the inclusive is true
+func (p *PersistentTree[Key, Value]) higherNode(key Key,
+	inclusive bool) (found *pnode[Key, Value]) {
+
+	for current := p.root; current != nil; {
+		switch c := p.cmp(key, current.key); {
+		case c == 0 && inclusive:
+			return current
+		case c >= 0:
+			current = current.right
+		default:
+			found, current = current, current.left
+		}
+	}
+	return
+}
+
+func (n *pnode[Key, Value]) result() (key Key, value Value, ok bool) {
+	if n == nil {
+		return
+	}
+	return n.key, n.value, true
+}
+
+// Floor returns the greatest key less than or equal to the given one and
+// its value O(logn). It returns false, if there is no such key.
+func (p *PersistentTree[Key, Value]) Floor(key Key) (Key, Value, bool) {
+	return p.lowerNode(key, true).result()
+}
+
+// Ceiling returns the smallest key greater than or equal to the given one
+// and its value O(logn). It returns false, if there is no such key.
+func (p *PersistentTree[Key, Value]) Ceiling(key Key) (Key, Value, bool) {
+	return p.higherNode(key, true).result()
+}
+
+// Lower returns the greatest key strictly less than the given one and
+// its value O(logn). It returns false, if there is no such key.
+func (p *PersistentTree[Key, Value]) Lower(key Key) (Key, Value, bool) {
+	return p.lowerNode(key, false).result()
+}
+
+// Higher returns the smallest key strictly greater than the given one and
+// its value O(logn). It returns false, if there is no such key.
+func (p *PersistentTree[Key, Value]) Higher(key Key) (Key, Value, bool) {
+	return p.higherNode(key, false).result()
+}
+
+// Get O(logn). It returns zero value, if key doesn't exist.
+func (p *PersistentTree[Key, Value]) Get(key Key) (value Value) {
+	value, _ = p.GetEx(key)
+	return
+}
+
+// GetEx O(logn). It returns false, if key doesn't exist.
+func (p *PersistentTree[Key, Value]) GetEx(key Key) (value Value, ok bool) {
+	if n := p.findNode(key); n != nil {
+		return n.value, true
+	}
+	return
+}
+
+// IsExist O(logn)
+func (p *PersistentTree[Key, Value]) IsExist(key Key) bool {
+	return p.findNode(key) != nil
+}
+
+// Len O(1)
+func (p *PersistentTree[Key, Value]) Len() int {
+	return p.len
+}
+
+// Max returns maximum key and its value O(logn). It returns zero values
+// for an empty tree.
+func (p *PersistentTree[Key, Value]) Max() (key Key, value Value) {
+	if p.root == nil {
+		return
+	}
+	var current = p.root
+	for current.right != nil {
+		current = current.right
+	}
+	return current.key, current.value
+}
+
+// Min returns minimum key and its value O(logn). It returns zero values
+// for an empty tree.
+func (p *PersistentTree[Key, Value]) Min() (key Key, value Value) {
+	if p.root == nil {
+		return
+	}
+	var current = p.root
+	for current.left != nil {
+		current = current.left
+	}
+	return current.key, current.value
+}
+
+func (p *PersistentTree[Key, Value]) walkLeft(n *pnode[Key, Value],
+	from, to Key, walkFunc WalkFunc[Key, Value]) (err error) {
+
+	if n == nil {
+		return
+	}
+
+	var cf, ct = p.cmp(n.key, from), p.cmp(n.key, to)
+
+	if cf > 0 {
+		if err = p.walkLeft(n.left, from, to, walkFunc); err != nil {
+			return
+		}
+	}
+	if cf >= 0 && ct <= 0 {
+		if err = walkFunc(n.key, n.value); err != nil {
+			return
+		}
+	}
+	if ct < 0 {
+		err = p.walkLeft(n.right, from, to, walkFunc)
+	}
+	return
+}
+
+func (p *PersistentTree[Key, Value]) walkRight(n *pnode[Key, Value],
+	from, to Key, walkFunc WalkFunc[Key, Value]) (err error) {
+
+	if n == nil {
+		return
+	}
+
+	var cf, ct = p.cmp(n.key, from), p.cmp(n.key, to)
+
+	if cf < 0 {
+		if err = p.walkRight(n.right, from, to, walkFunc); err != nil {
+			return
+		}
+	}
+	if cf <= 0 && ct >= 0 {
+		if err = walkFunc(n.key, n.value); err != nil {
+			return
+		}
+	}
+	if ct > 0 {
+		err = p.walkRight(n.left, from, to, walkFunc)
+	}
+	return
+}
+
+// Walk on the PersistentTree. It works the same way as Tree.Walk does.
+// Unlike the Tree, the PersistentTree never changes, thus it's safe to
+// set new versions inside the WalkFunc.
+func (p *PersistentTree[Key, Value]) Walk(from, to Key,
+	walkFunc WalkFunc[Key, Value]) error {
+
+	if p.cmp(from, to) <= 0 {
+		return p.walkLeft(p.root, from, to, walkFunc)
+	}
+	return p.walkRight(p.root, from, to, walkFunc)
+}
+
+// Slice returns all values at given range if any.
+func (p *PersistentTree[Key, Value]) Slice(from, to Key) (vals []Value) {
+	p.Walk(from, to, func(_ Key, value Value) error {
+		vals = append(vals, value)
+		return nil
+	})
+	return
+}
+
+// SliceKeys returns all keys at given range if any.
+func (p *PersistentTree[Key, Value]) SliceKeys(from, to Key) (keys []Key) {
+	p.Walk(from, to, func(key Key, _ Value) error {
+		keys = append(keys, key)
+		return nil
+	})
+	return
+}
+
+// All returns an iterator over all key-value pairs of the PersistentTree
+// in ascending order of keys.
+func (p *PersistentTree[Key, Value]) All() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		var all func(n *pnode[Key, Value]) bool
+		all = func(n *pnode[Key, Value]) bool {
+			return n == nil || all(n.left) && yield(n.key, n.value) &&
+				all(n.right)
+		}
+		all(p.root)
+	}
+}
+
+// Range returns an iterator over key-value pairs of the PersistentTree at
+// given range. See Tree.Range for details.
+func (p *PersistentTree[Key, Value]) Range(from,
+	to Key) iter.Seq2[Key, Value] {
+
+	return func(yield func(Key, Value) bool) {
+		p.Walk(from, to, func(key Key, value Value) error {
+			if !yield(key, value) {
+				return ErrStop
+			}
+			return nil
+		})
+	}
+}
@@ -0,0 +1,194 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"math/rand"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// checkPersistent checks properties of a left-leaning RB-Tree
+func checkPersistent[Key, Value any](t *testing.T,
+	p *PersistentTree[Key, Value]) {
+
+	t.Helper()
+
+	var check func(n *pnode[Key, Value]) (size, blackHeight int)
+	check = func(n *pnode[Key, Value]) (size, blackHeight int) {
+		if n == nil {
+			return 0, 1
+		}
+		assert.False(t, n.right.isRed(), "right leaning red")
+		if n.isRed() {
+			assert.False(t, n.left.isRed(), "red-red")
+		}
+		if n.left != nil {
+			assert.Less(t, p.cmp(n.left.key, n.key), 0, "order")
+		}
+		if n.right != nil {
+			assert.Greater(t, p.cmp(n.right.key, n.key), 0, "order")
+		}
+		var ls, lh = check(n.left)
+		var rs, rh = check(n.right)
+		assert.Equal(t, lh, rh, "black height")
+		assert.Equal(t, ls+rs+1, n.size, "size")
+		if n.color == black {
+			lh++
+		}
+		return n.size, lh
+	}
+
+	assert.False(t, p.root.isRed(), "red root")
+	var size, _ = check(p.root)
+	assert.Equal(t, p.len, size, "len")
+}
+
+func TestPersistentTree(t *testing.T) {
+
+	var p0 = NewPersistent[int, string]()
+	assert.Equal(t, 0, p0.Len())
+	var key, value = p0.Min()
+	assert.Zero(t, key)
+	assert.Zero(t, value)
+	assert.Nil(t, p0.Slice(0, 10))
+
+	var p1 = p0.Set(1, "one").Set(2, "two").Set(3, "three")
+	var p2 = p1.Set(2, "TWO").Del(1)
+	var p3 = p2.Del(100)
+
+	assert.Equal(t, 0, p0.Len())
+	assert.Equal(t, []string{"one", "two", "three"}, p1.Slice(0, 10))
+	assert.Equal(t, []string{"TWO", "three"}, p2.Slice(0, 10))
+	assert.True(t, p2 == p3)
+
+	assert.Equal(t, "two", p1.Get(2))
+	assert.Equal(t, "", p2.Get(1))
+	var v, ok = p2.GetEx(3)
+	assert.True(t, ok)
+	assert.Equal(t, "three", v)
+	assert.False(t, p2.IsExist(1))
+	assert.True(t, p1.IsExist(1))
+
+	key, value = p1.Min()
+	assert.Equal(t, 1, key)
+	assert.Equal(t, "one", value)
+	key, value = p1.Max()
+	assert.Equal(t, 3, key)
+	assert.Equal(t, "three", value)
+
+	assert.Equal(t, []int{3, 2, 1}, p1.SliceKeys(10, 0))
+	assert.Equal(t, []int{2}, p1.SliceKeys(2, 2))
+
+	var keys []int
+	for key := range p1.Range(3, 1) {
+		keys = append(keys, key)
+		if len(keys) == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []int{3, 2}, keys)
+	keys = keys[:0]
+	for key := range p1.All() {
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []int{1, 2, 3}, keys)
+
+	var err = p1.Walk(0, 10, func(int, string) error { return ErrStop })
+	assert.ErrorIs(t, err, ErrStop)
+}
+
+func Test_randomPersistent(t *testing.T) {
+
+	var (
+		rnd = rand.New(rand.NewSource(42))
+
+		versions []*PersistentTree[int, int]
+		models   []map[int]int
+
+		p     = NewPersistent[int, int]()
+		model = map[int]int{}
+	)
+
+	for i := 0; i < 5000; i++ {
+		var key = rnd.Intn(500)
+		if rnd.Intn(3) == 0 {
+			p = p.Del(key)
+			delete(model, key)
+		} else {
+			p = p.Set(key, i)
+			model[key] = i
+		}
+		if i%100 == 0 {
+			checkPersistent(t, p)
+			versions = append(versions, p)
+			var copied = make(map[int]int, len(model))
+			for k, v := range model {
+				copied[k] = v
+			}
+			models = append(models, copied)
+		}
+	}
+
+	// all versions are consistent snapshots
+	for i, p := range versions {
+		var keys = make([]int, 0, len(models[i]))
+		for k := range models[i] {
+			keys = append(keys, k)
+		}
+		sort.Ints(keys)
+		assert.Equal(t, len(keys), p.Len())
+		if !assert.Equal(t, keys, slices.Collect(func(yield func(int) bool) {
+			for k, v := range p.All() {
+				if v != models[i][k] || !yield(k) {
+					return
+				}
+			}
+		})) {
+			return
+		}
+	}
+
+	// delete all
+	for key := range model {
+		p = p.Del(key)
+	}
+	assert.Equal(t, 0, p.Len())
+	checkPersistent(t, p)
+}
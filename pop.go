@@ -0,0 +1,49 @@
+package rbtree
+
+// PopMin deletes the minimum key and returns it with its value O(logn).
+// It returns false, if the Tree is empty.
+func (t *Tree[Key, Value]) PopMin() (key Key, value Value, ok bool) {
+	if t.min == t.sentinel {
+		return
+	}
+	key, value = t.min.key, t.min.value
+	t.deleteNode(t.min)
+	return key, value, true
+}
+
+// PopMax deletes the maximum key and returns it with its value O(logn).
+// It returns false, if the Tree is empty.
+func (t *Tree[Key, Value]) PopMax() (key Key, value Value, ok bool) {
+	if t.max == t.sentinel {
+		return
+	}
+	key, value = t.max.key, t.max.value
+	t.deleteNode(t.max)
+	return key, value, true
+}
+
+// PopMinN deletes up to n minimum keys and returns them with values in
+// ascending order O(logn + n).
+func (t *Tree[Key, Value]) PopMinN(n int) (keys []Key, values []Value) {
+
+	n = min(n, t.len)
+	if n <= 0 {
+		return
+	}
+
+	keys, values = make([]Key, 0, n), make([]Value, 0, n)
+	var x = t.min
+	for ; len(keys) < n; x = t.successor(x) {
+		keys, values = append(keys, x.key), append(values, x.value)
+	}
+
+	if x == t.sentinel {
+		t.Empty()
+		return
+	}
+	var l, _, r, _ = t.split(t.ownRoot(), t.blackHeight(t.root), x.key,
+		false)
+	t.reset(r, t.len-n)
+	t.recordCut(l)
+	return
+}
@@ -0,0 +1,189 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree_PopMin(t *testing.T) {
+
+	var tr = New[int, string]()
+	var _, _, ok = tr.PopMin()
+	assert.False(t, ok)
+	_, _, ok = tr.PopMax()
+	assert.False(t, ok)
+
+	tr.Set(2, "two")
+	tr.Set(1, "one")
+	tr.Set(3, "three")
+
+	var key, value, _ = tr.PopMin()
+	assert.Equal(t, 1, key)
+	assert.Equal(t, "one", value)
+	checkTree(t, tr)
+	key, value, _ = tr.PopMax()
+	assert.Equal(t, 3, key)
+	assert.Equal(t, "three", value)
+	checkTree(t, tr)
+	key, value, ok = tr.PopMax()
+	assert.True(t, ok)
+	assert.Equal(t, 2, key)
+	assert.Equal(t, "two", value)
+	assert.Equal(t, 0, tr.Len())
+	checkTree(t, tr)
+}
+
+func TestTree_PopMinN(t *testing.T) {
+
+	var tr = New[int, int]()
+	for i := 0; i < 100; i++ {
+		tr.Set(i, -i)
+	}
+
+	var keys, values = tr.PopMinN(0)
+	assert.Nil(t, keys)
+	assert.Nil(t, values)
+
+	keys, values = tr.PopMinN(3)
+	assert.Equal(t, []int{0, 1, 2}, keys)
+	assert.Equal(t, []int{0, -1, -2}, values)
+	assert.Equal(t, 97, tr.Len())
+	checkTree(t, tr)
+	var min, _ = tr.Min()
+	assert.Equal(t, 3, min)
+
+	keys, _ = tr.PopMinN(1000)
+	assert.Len(t, keys, 97)
+	assert.Equal(t, 99, keys[96])
+	assert.Equal(t, 0, tr.Len())
+	checkTree(t, tr)
+}
+
+func Test_randomPop(t *testing.T) {
+
+	var (
+		rnd  = rand.New(rand.NewSource(42))
+		tr   = New[int, int]()
+		want []int
+	)
+
+	for i := 0; i < 2000; i++ {
+		var key = rnd.Intn(1000)
+		if tr.Set(key, key) {
+			want = append(want, key)
+		}
+	}
+	slices.Sort(want)
+
+	for len(want) > 0 {
+		switch rnd.Intn(3) {
+		case 0:
+			var key, _, _ = tr.PopMin()
+			assert.Equal(t, want[0], key)
+			want = want[1:]
+		case 1:
+			var key, _, _ = tr.PopMax()
+			assert.Equal(t, want[len(want)-1], key)
+			want = want[:len(want)-1]
+		default:
+			var n = rnd.Intn(20)
+			var keys, _ = tr.PopMinN(n)
+			n = min(n, len(want))
+			assert.Len(t, keys, n)
+			assert.True(t, slices.Equal(want[:n], keys))
+			want = want[n:]
+		}
+		if !assert.Equal(t, len(want), tr.Len()) {
+			return
+		}
+		checkTree(t, tr)
+	}
+}
+
+func TestTreeThreadSafe_PopMin(t *testing.T) {
+
+	var tts = NewThreadSafe[int, int]()
+	for i := 0; i < 1000; i++ {
+		tts.Set(i, i)
+	}
+
+	var err = tts.Update(func(tx *Tree[int, int]) error {
+		tx.PopMin()
+		tx.PopMax()
+		tx.PopMinN(10)
+		return errors.New("rollback")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, 1000, tts.Len())
+	checkTree(t, tts.tree)
+
+	var (
+		wg     sync.WaitGroup
+		mx     sync.Mutex
+		popped = map[int]bool{}
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ { // 5 keys per iteration
+				var key, _, ok = tts.PopMin()
+				var max, _, _ = tts.PopMax()
+				var keys, _ = tts.PopMinN(3)
+				mx.Lock()
+				assert.True(t, ok)
+				assert.False(t, popped[key])
+				assert.False(t, popped[max])
+				popped[key], popped[max] = true, true
+				for _, k := range keys {
+					assert.False(t, popped[k])
+					popped[k] = true
+				}
+				mx.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	assert.Len(t, popped, 1000)
+	assert.Equal(t, 0, tts.Len())
+}
@@ -0,0 +1,67 @@
+package rbtree
+
+// rank returns number of keys less than the given one, or less than or
+// equal to the given one if the inclusive is true.
+func (t *Tree[Key, Value]) rank(key Key, inclusive bool) (rank int) {
+
+	var current = t.root
+
+	for current != t.sentinel {
+		var c = t.cmp(key, current.key)
+		if c < 0 || (c == 0 && !inclusive) {
+			current = current.left
+		} else {
+			rank += current.left.size + 1
+			current = current.right
+		}
+	}
+
+	return
+}
+
+// selectNode returns i-th node in ascending order of keys or sentinel.
+func (t *Tree[Key, Value]) selectNode(i int) *node[Key, Value] {
+
+	if i < 0 || i >= t.len {
+		return t.sentinel
+	}
+
+	var current = t.root
+
+	for current != t.sentinel {
+		switch left := current.left.size; {
+		case i < left:
+			current = current.left
+		case i == left:
+			return current
+		default:
+			i -= left + 1
+			current = current.right
+		}
+	}
+
+	return current // never happens
+}
+
+// Rank returns number of keys less than the given one O(logn). It is
+// position of the key in the Tree, if the key exists.
+func (t *Tree[Key, Value]) Rank(key Key) int {
+	return t.rank(key, false)
+}
+
+// Select returns i-th key in ascending order and its value O(logn).
+// The i starts from zero. It returns false, if the i is out of range.
+func (t *Tree[Key, Value]) Select(i int) (Key, Value, bool) {
+	var node = t.selectNode(i)
+	return node.key, node.value, node != t.sentinel
+}
+
+// CountRange returns number of keys at given range O(logn). The range
+// is inclusive, and the from can be greater than the to, the same way as
+// for the Walk.
+func (t *Tree[Key, Value]) CountRange(from, to Key) int {
+	if t.cmp(from, to) > 0 {
+		from, to = to, from
+	}
+	return t.rank(to, true) - t.rank(from, false)
+}
@@ -0,0 +1,128 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree_Rank(t *testing.T) {
+
+	var tr = New[int, string]()
+	assert.Zero(t, tr.Rank(0))
+	var _, _, ok = tr.Select(0)
+	assert.False(t, ok)
+	assert.Zero(t, tr.CountRange(math.MinInt, math.MaxInt))
+
+	tr.Set(10, "ten")
+	tr.Set(20, "twenty")
+	tr.Set(30, "thirty")
+
+	assert.Equal(t, 0, tr.Rank(5))
+	assert.Equal(t, 0, tr.Rank(10))
+	assert.Equal(t, 1, tr.Rank(11))
+	assert.Equal(t, 1, tr.Rank(20))
+	assert.Equal(t, 2, tr.Rank(30))
+	assert.Equal(t, 3, tr.Rank(31))
+
+	var key, value, _ = tr.Select(1)
+	assert.Equal(t, 20, key)
+	assert.Equal(t, "twenty", value)
+	_, _, ok = tr.Select(-1)
+	assert.False(t, ok)
+	_, _, ok = tr.Select(3)
+	assert.False(t, ok)
+
+	assert.Equal(t, 3, tr.CountRange(math.MinInt, math.MaxInt))
+	assert.Equal(t, 3, tr.CountRange(math.MaxInt, math.MinInt))
+	assert.Equal(t, 2, tr.CountRange(10, 20))
+	assert.Equal(t, 2, tr.CountRange(30, 11))
+	assert.Equal(t, 1, tr.CountRange(20, 20))
+	assert.Equal(t, 0, tr.CountRange(21, 29))
+}
+
+func Test_randomRank(t *testing.T) {
+
+	var (
+		tr = New[int, int]()
+		kv = make(map[int]int)
+	)
+
+	for i := 0; i < Count; i++ {
+		var k = rand.Intn(Count)
+		if rand.Intn(3) == 0 {
+			tr.Del(k)
+			delete(kv, k)
+		} else {
+			tr.Set(k, -k)
+			kv[k] = -k
+		}
+	}
+	checkTree(t, tr)
+
+	var keys = make([]int, 0, len(kv))
+	for k := range kv {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+
+	for i, k := range keys {
+		assert.Equal(t, i, tr.Rank(k))
+		var key, value, ok = tr.Select(i)
+		assert.True(t, ok)
+		assert.Equal(t, k, key)
+		assert.Equal(t, -k, value)
+	}
+
+	for i := 0; i < 100; i++ {
+		var (
+			from, to = rand.Intn(Count), rand.Intn(Count)
+			count    = len(tr.SliceKeys(from, to))
+		)
+		assert.Equal(t, count, tr.CountRange(from, to))
+		assert.Equal(t, sort.SearchInts(keys, from), tr.Rank(from))
+	}
+
+	for k := range kv {
+		tr.Del(k)
+	}
+	checkTree(t, tr)
+}
@@ -37,7 +37,11 @@
 package rbtree
 
 import (
+	"cmp"
 	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
 
 	"golang.org/x/exp/constraints"
 )
@@ -49,31 +53,47 @@ const (
 	black color = false
 )
 
-type node[Key constraints.Ordered, Value any] struct {
+type node[Key, Value any] struct {
 	left   *node[Key, Value]
 	right  *node[Key, Value]
 	parent *node[Key, Value]
+	size   int    // number of nodes of the subtree
+	gen    uint64 // generation of the Tree, that owns the node, see Clone
 	color  color
+	value  Value // not the last, a zero-size last field takes memory
 	key    Key
-	value  Value
 }
 
+// CompareFunc compares two keys. It returns a negative number if the a is
+// less than the b, a positive number if the a is greater than the b, and
+// zero if they are equal. Use cmp.Compare, bytes.Compare, strings.Compare,
+// time.Time.Compare or similar.
+type CompareFunc[Key any] func(a, b Key) int
+
 // Tree is the RB-tree
-type Tree[Key constraints.Ordered, Value any] struct {
+type Tree[Key, Value any] struct {
 	sentinel *node[Key, Value]
 	root     *node[Key, Value]
 	len      int
+	cmp      CompareFunc[Key]
+	ordered  *ordered[Key, Value] // not nil for ordered keys
+	min      *node[Key, Value]    // the leftmost node or the sentinel
+	max      *node[Key, Value]    // the rightmost node or the sentinel
+	gen      atomic.Uint64        // generation of owned nodes
+	stale    bool                 // parents of shared nodes can be wrong
+	journal  *journal             // not nil inside a transaction
+
+	// augment recomputes data kept in value of the node from its
+	// children, it's nil for a plain Tree
+	augment func(n *node[Key, Value])
 }
 
 func (t *Tree[Key, Value]) rotateLeft(x *node[Key, Value]) {
 
-	var y = x.right
+	var y = t.ownRight(x)
 
 	x.right = y.left
-
-	if y.left != t.sentinel {
-		y.left.parent = x
-	}
+	t.setParent(y.left, x)
 
 	if y != t.sentinel {
 		y.parent = x.parent
@@ -94,17 +114,22 @@ func (t *Tree[Key, Value]) rotateLeft(x *node[Key, Value]) {
 	if x != t.sentinel {
 		x.parent = y
 	}
+
+	y.size = x.size
+	x.size = x.left.size + x.right.size + 1
+
+	if t.augment != nil {
+		t.augment(x)
+		t.augment(y)
+	}
 }
 
 func (t *Tree[Key, Value]) rotateRight(x *node[Key, Value]) {
 
-	var y = x.left
+	var y = t.ownLeft(x)
 
 	x.left = y.right
-
-	if y.right != t.sentinel {
-		y.right.parent = x
-	}
+	t.setParent(y.right, x)
 
 	if y != t.sentinel {
 		y.parent = x.parent
@@ -125,9 +150,19 @@ func (t *Tree[Key, Value]) rotateRight(x *node[Key, Value]) {
 	if x != t.sentinel {
 		x.parent = y
 	}
+
+	y.size = x.size
+	x.size = x.left.size + x.right.size + 1
+
+	if t.augment != nil {
+		t.augment(x)
+		t.augment(y)
+	}
 }
 
-func (t *Tree[Key, Value]) insertFixup(x *node[Key, Value]) {
+// insertFixup restores the properties after insertion of the red x, it
+// returns true if black height of the tree is increased
+func (t *Tree[Key, Value]) insertFixup(x *node[Key, Value]) (grown bool) {
 
 	for x != t.root && x.parent.color == red {
 
@@ -136,6 +171,7 @@ func (t *Tree[Key, Value]) insertFixup(x *node[Key, Value]) {
 			var y = x.parent.parent.right
 
 			if y.color == red {
+				y = t.ownRight(x.parent.parent)
 				x.parent.color = black
 				y.color = black
 				x.parent.parent.color = red
@@ -155,6 +191,7 @@ func (t *Tree[Key, Value]) insertFixup(x *node[Key, Value]) {
 			var y = x.parent.parent.left
 
 			if y.color == red {
+				y = t.ownLeft(x.parent.parent)
 				x.parent.color = black
 				y.color = black
 				x.parent.parent.color = red
@@ -171,147 +208,259 @@ func (t *Tree[Key, Value]) insertFixup(x *node[Key, Value]) {
 		}
 	}
 
+	grown = t.root.color == red
 	t.root.color = black
+	return
 }
 
-func (t *Tree[Key, Value]) insertNode(key Key, value Value, overwrite bool) (
-	added bool) {
+// descend returns node with given key or the sentinel. If the key doesn't
+// exist, it also returns parent for new node and result of comparison of
+// the key with key of the parent, see insertAt. The d is added to sizes of
+// all nodes of the path above the returned one, it's 1 for an insertion
+// and -1 for a deletion, thus the sizes are changed without a second walk.
+// Use the resize to revert the change, if nothing is inserted or deleted.
+// Shared nodes of the path are copied, thus all returned nodes and their
+// ancestors can be changed.
+func (t *Tree[Key, Value]) descend(key Key, d int) (current,
+	parent *node[Key, Value], c int) {
 
-	var (
-		current = t.root
+	if t.ordered != nil {
+		return t.ordered.descend(t, key, d)
+	}
 
-		parent *node[Key, Value]
-	)
+	var gen = t.gen.Load()
+	current = t.root
 
 	for current != t.sentinel {
-		if key == current.key {
-			if overwrite {
-				current.value = value
-				return
-			}
+		if current.gen != gen {
+			current = t.copyNode(current, parent, c < 0)
+		}
+		c = t.cmp(key, current.key)
+		if c == 0 {
 			return
 		}
+		current.size += d
 		parent = current
-		if key < current.key {
+		if c < 0 {
 			current = current.left
 		} else {
 			current = current.right
 		}
 	}
 
+	return
+}
+
+// resize adds the d to sizes of the n and all its ancestors
+func (t *Tree[Key, Value]) resize(n *node[Key, Value], d int) {
+	for ; n != nil; n = n.parent {
+		n.size += d
+	}
+}
+
+// insertAt inserts new node as a child of the parent found by the descend,
+// sizes of the parent and its ancestors are increased already
+func (t *Tree[Key, Value]) insertAt(parent *node[Key, Value], c int,
+	key Key, value Value) {
+
 	var x = &node[Key, Value]{
 		value:  value,
 		parent: parent,
 		left:   t.sentinel,
 		right:  t.sentinel,
+		size:   1,
+		gen:    t.gen.Load(),
 		color:  red,
 		key:    key,
 	}
 
 	if parent != nil {
-		if key < parent.key {
+		if c < 0 {
 			parent.left = x
+			if parent == t.min {
+				t.min = x
+			}
 		} else {
 			parent.right = x
+			if parent == t.max {
+				t.max = x
+			}
 		}
 	} else {
-		t.root = x
+		t.root, t.min, t.max = x, x, x
 	}
 
+	t.augmentPath(x)
+
+	t.recordSet(key, value, false)
+
 	t.insertFixup(x)
 	t.len++
+}
+
+// setValue replaces value of existing node
+func (t *Tree[Key, Value]) setValue(n *node[Key, Value], value Value) {
+	n = t.own(n)
+	t.recordSet(n.key, n.value, true)
+	n.value = value
+	t.augmentPath(n)
+}
+
+// augmentPath recomputes augmented data of the n and all its ancestors
+func (t *Tree[Key, Value]) augmentPath(n *node[Key, Value]) {
+	if t.augment == nil {
+		return
+	}
+	for ; n != nil && n != t.sentinel; n = n.parent {
+		t.augment(n)
+	}
+}
+
+// newTree returns new empty tree with comparison function and augmentation
+// of the Tree
+func (t *Tree[Key, Value]) newTree() (tr *Tree[Key, Value]) {
+	tr = NewFunc[Key, Value](t.cmp)
+	tr.ordered, tr.augment = t.ordered, t.augment
+	tr.gen.Store(t.gen.Load()) // nodes can be moved from the Tree
+	return
+}
+
+func (t *Tree[Key, Value]) insertNode(key Key, value Value, overwrite bool) (
+	added bool) {
+
+	var current, parent, c = t.descend(key, 1)
+
+	if current != t.sentinel {
+		t.resize(current.parent, -1)
+		if overwrite {
+			t.setValue(current, value)
+		}
+		return
+	}
+
+	t.insertAt(parent, c, key, value)
 	return true
 }
 
-func (t *Tree[Key, Value]) deleteFixup(x *node[Key, Value]) {
+// deleteFixup restores the properties, the x can be the sentinel, thus
+// its parent is passed explicitly, because the sentinel is never changed
+func (t *Tree[Key, Value]) deleteFixup(x, parent *node[Key, Value]) {
 
 	for x != t.root && x.color == black {
 
-		if x == x.parent.left {
-			var w = x.parent.right
+		if x == parent.left {
+			var w = t.ownRight(parent)
 
 			if w.color == red {
 				w.color = black
-				x.parent.color = red
-				t.rotateLeft(x.parent)
-				w = x.parent.right
+				parent.color = red
+				t.rotateLeft(parent)
+				w = t.ownRight(parent)
 			}
 
 			if w.left.color == black && w.right.color == black {
 				w.color = red
-				x = x.parent
+				x, parent = parent, parent.parent
 			} else {
 				if w.right.color == black {
-					w.left.color = black
+					t.ownLeft(w).color = black
 					w.color = red
 					t.rotateRight(w)
-					w = x.parent.right
+					w = parent.right
 				}
-				w.color = x.parent.color
-				x.parent.color = black
-				w.right.color = black
-				t.rotateLeft(x.parent)
+				w.color = parent.color
+				parent.color = black
+				t.ownRight(w).color = black
+				t.rotateLeft(parent)
 				x = t.root
 			}
 
 		} else {
 
-			var w = x.parent.left
+			var w = t.ownLeft(parent)
 
 			if w.color == red {
 				w.color = black
-				x.parent.color = red
-				t.rotateRight(x.parent)
-				w = x.parent.left
+				parent.color = red
+				t.rotateRight(parent)
+				w = t.ownLeft(parent)
 			}
 
 			if w.right.color == black && w.left.color == black {
 				w.color = red
-				x = x.parent
+				x, parent = parent, parent.parent
 			} else {
 				if w.left.color == black {
-					w.right.color = black
+					t.ownRight(w).color = black
 					w.color = red
 					t.rotateLeft(w)
-					w = x.parent.left
+					w = parent.left
 				}
-				w.color = x.parent.color
-				x.parent.color = black
-				w.left.color = black
-				t.rotateRight(x.parent)
+				w.color = parent.color
+				parent.color = black
+				t.ownLeft(w).color = black
+				t.rotateRight(parent)
 				x = t.root
 			}
 
 		}
 	}
 
-	x.color = black
+	if x != t.sentinel {
+		x.color = black
+	}
 }
 
 // silent
 func (t *Tree[Key, Value]) deleteNode(z *node[Key, Value]) {
-
-	var x, y *node[Key, Value]
 	if z == t.sentinel {
 		return
 	}
+	z = t.own(z)
+	t.resize(z.parent, -1)
+	t.remove(z)
+}
+
+// remove deletes the owned z, sizes of ancestors of which are decreased
+// already
+func (t *Tree[Key, Value]) remove(z *node[Key, Value]) {
+
+	var x, y *node[Key, Value]
+
+	t.recordSet(z.key, z.value, true)
+
+	// the min and the max have one child at most, thus they are removed
+	if z == t.min {
+		t.min = t.successor(z)
+	}
+	if z == t.max {
+		t.max = t.predecessor(z)
+	}
 
 	if z.left == t.sentinel || z.right == t.sentinel {
 		y = z
 	} else {
-		y = z.right
+		z.size-- // the y is removed from subtree of the z
+		y = t.ownRight(z)
 		for y.left != t.sentinel {
-			y = y.left
+			y.size--
+			y = t.ownLeft(y)
 		}
 	}
 
 	if y.left != t.sentinel {
-		x = y.left
+		x = t.ownLeft(y)
 	} else {
-		x = y.right
+		x = t.ownRight(y)
+	}
+
+	if y == t.max && y != z {
+		t.max = z // the y is moved to the z
 	}
 
-	x.parent = y.parent
+	if x != t.sentinel {
+		x.parent = y.parent
+	}
 
 	if y.parent != nil {
 		if y == y.parent.left {
@@ -327,9 +476,10 @@ func (t *Tree[Key, Value]) deleteNode(z *node[Key, Value]) {
 		z.key = y.key
 		z.value = y.value
 	}
+	t.augmentPath(y.parent) // the z is one of the ancestors
 
 	if y.color == black {
-		t.deleteFixup(x)
+		t.deleteFixup(x, y.parent)
 	}
 
 	t.len--
@@ -337,13 +487,18 @@ func (t *Tree[Key, Value]) deleteNode(z *node[Key, Value]) {
 
 func (t *Tree[Key, Value]) findNode(key Key) *node[Key, Value] {
 
+	if t.ordered != nil {
+		return t.ordered.findNode(t, key)
+	}
+
 	var current = t.root
 
 	for current != t.sentinel {
-		if key == current.key {
+		var c = t.cmp(key, current.key)
+		if c == 0 {
 			return current
 		}
-		if key < current.key {
+		if c < 0 {
 			current = current.left
 		} else {
 			current = current.right
@@ -353,9 +508,107 @@ func (t *Tree[Key, Value]) findNode(key Key) *node[Key, Value] {
 	return current // root sentinel
 }
 
-func newSentinel[Key constraints.Ordered, Value any]() (
+// ceilingNode returns node with the smallest key >= given one or sentinel.
+func (t *Tree[Key, Value]) ceilingNode(key Key) *node[Key, Value] {
+
+	var (
+		current = t.root
+		found   = t.sentinel
+	)
+
+	for current != t.sentinel {
+		var c = t.cmp(key, current.key)
+		if c == 0 {
+			return current
+		}
+		if c < 0 {
+			found = current
+			current = current.left
+		} else {
+			current = current.right
+		}
+	}
+
+	return found
+}
+
+// floorNode returns node with the greatest key <= given one or sentinel.
+func (t *Tree[Key, Value]) floorNode(key Key) *node[Key, Value] {
+
+	var (
+		current = t.root
+		found   = t.sentinel
+	)
+
+	for current != t.sentinel {
+		var c = t.cmp(key, current.key)
+		if c == 0 {
+			return current
+		}
+		if c < 0 {
+			current = current.left
+		} else {
+			found = current
+			current = current.right
+		}
+	}
+
+	return found
+}
+
+// lowerNode returns node with the greatest key < given one or sentinel.
+func (t *Tree[Key, Value]) lowerNode(key Key) *node[Key, Value] {
+
+	var (
+		current = t.root
+		found   = t.sentinel
+	)
+
+	for current != t.sentinel {
+		if t.cmp(key, current.key) <= 0 {
+			current = current.left
+		} else {
+			found = current
+			current = current.right
+		}
+	}
+
+	return found
+}
+
+// higherNode returns node with the smallest key > given one or sentinel.
+func (t *Tree[Key, Value]) higherNode(key Key) *node[Key, Value] {
+
+	var (
+		current = t.root
+		found   = t.sentinel
+	)
+
+	for current != t.sentinel {
+		if t.cmp(key, current.key) < 0 {
+			found = current
+			current = current.left
+		} else {
+			current = current.right
+		}
+	}
+
+	return found
+}
+
+// sentinels is a sentinel per node type, the sentinel is never changed,
+// thus all trees of the same type share it, and nodes can be moved from
+// one tree to another without changes
+var sentinels sync.Map // reflect.Type -> *node[Key, Value]
+
+func newSentinel[Key, Value any]() (
 	sentinel *node[Key, Value]) {
 
+	var typ = reflect.TypeFor[*node[Key, Value]]()
+	if s, ok := sentinels.Load(typ); ok {
+		return s.(*node[Key, Value])
+	}
+
 	var (
 		zeroKey   Key
 		zeroValue Value
@@ -365,23 +618,41 @@ func newSentinel[Key constraints.Ordered, Value any]() (
 		left:   nil,
 		right:  nil,
 		parent: nil,
+		size:   0,
 		color:  black,
 		key:    zeroKey,
 		value:  zeroValue,
 	}
 
 	sentinel.left, sentinel.right = sentinel, sentinel
-	return
+
+	var s, _ = sentinels.LoadOrStore(typ, sentinel)
+	return s.(*node[Key, Value])
 }
 
 // New creates the new RB-Tree
-func New[Key constraints.Ordered, Value any]() *Tree[Key, Value] {
+func New[Key constraints.Ordered, Value any]() (tr *Tree[Key, Value]) {
+	tr = NewFunc[Key, Value](cmp.Compare[Key])
+	tr.ordered = newOrdered[Key, Value]()
+	return
+}
+
+// NewFunc creates the new RB-Tree that uses given function to compare
+// keys. It allows to use any type as a key, for example:
+//
+//	var tr = rbtree.NewFunc[time.Time, string](time.Time.Compare)
+//
+// The function must define a strict weak ordering of keys.
+func NewFunc[Key, Value any](cmp CompareFunc[Key]) *Tree[Key, Value] {
 
 	var sentinel = newSentinel[Key, Value]()
 
 	return &Tree[Key, Value]{
 		sentinel: sentinel,
 		root:     sentinel,
+		min:      sentinel,
+		max:      sentinel,
+		cmp:      cmp,
 	}
 }
 
@@ -398,10 +669,13 @@ func (t *Tree[Key, Value]) SetNx(key Key, value Value) (added bool) {
 // Del deletes value by key. O(logn). It returns false,
 // if key doesn't exits.
 func (t *Tree[Key, Value]) Del(key Key) (deleted bool) {
-	var node = t.findNode(key)
-	deleted = (node != t.sentinel)
-	t.deleteNode(node)
-	return
+	var node, parent, _ = t.descend(key, -1)
+	if node == t.sentinel {
+		t.resize(parent, 1)
+		return
+	}
+	t.remove(node)
+	return true
 }
 
 // Get O(logn). It returns zero value, if key doesn't exist.
@@ -420,78 +694,104 @@ func (t *Tree[Key, Value]) IsExist(key Key) bool {
 	return t.findNode(key) != t.sentinel
 }
 
+// Floor returns the greatest key less than or equal to the given one and
+// its value O(logn). It returns false, if there is no such key.
+func (t *Tree[Key, Value]) Floor(key Key) (Key, Value, bool) {
+	var node = t.floorNode(key)
+	return node.key, node.value, node != t.sentinel
+}
+
+// Ceiling returns the smallest key greater than or equal to the given one
+// and its value O(logn). It returns false, if there is no such key.
+func (t *Tree[Key, Value]) Ceiling(key Key) (Key, Value, bool) {
+	var node = t.ceilingNode(key)
+	return node.key, node.value, node != t.sentinel
+}
+
+// Lower returns the greatest key strictly less than the given one and
+// its value O(logn). It returns false, if there is no such key.
+func (t *Tree[Key, Value]) Lower(key Key) (Key, Value, bool) {
+	var node = t.lowerNode(key)
+	return node.key, node.value, node != t.sentinel
+}
+
+// Higher returns the smallest key strictly greater than the given one and
+// its value O(logn). It returns false, if there is no such key.
+func (t *Tree[Key, Value]) Higher(key Key) (Key, Value, bool) {
+	var node = t.higherNode(key)
+	return node.key, node.value, node != t.sentinel
+}
+
 // Len O(1)
 func (t *Tree[Key, Value]) Len() int {
 	return t.len
 }
 
 // Move moves the value from one index to another. Silent.
-// It just changes index of value O(2logn).
+// It just changes index of value O(2logn). An existing value
+// of the newKey is overwritten, see MoveEx.
 func (t *Tree[Key, Value]) Move(oldKey, newKey Key) (moved bool) {
-	if n := t.findNode(oldKey); n != t.sentinel {
-		t.insertNode(newKey, n.value, true)
-		t.deleteNode(n)
-		return true
-	}
-	return // false
+	_, _, moved = t.MoveEx(oldKey, newKey)
+	return
 }
 
 // Empty makes the tree empty O(1).
 func (t *Tree[Key, Value]) Empty() {
-	t.root = t.sentinel
-	t.len = 0
+	t.recordRoot()
+	t.clear()
 }
 
-// Max returns maximum index and its value O(logn)
-func (t *Tree[Key, Value]) Max() (Key, Value) {
-	var current = t.root
+// clear makes the tree empty, it doesn't record undo
+func (t *Tree[Key, Value]) clear() {
+	t.reset(t.sentinel, 0)
+	t.stale = false
+}
 
-	for current.right != t.sentinel {
-		current = current.right
-	}
+// reset sets root of the Tree and finds the cached min and max.
+func (t *Tree[Key, Value]) reset(root *node[Key, Value], n int) {
+	t.root, t.len = root, n
+	t.min, t.max = t.minimum(root), t.maximum(root)
+}
 
-	return current.key, current.value
+// Max returns maximum index and its value O(1)
+func (t *Tree[Key, Value]) Max() (Key, Value) {
+	return t.max.key, t.max.value
 }
 
-// Min returns minimum indexed and its value O(logn)
+// Min returns minimum indexed and its value O(1)
 func (t *Tree[Key, Value]) Min() (Key, Value) {
-
-	var current = t.root
-
-	for current.left != t.sentinel {
-		current = current.left
-	}
-
-	return current.key, current.value
+	return t.min.key, t.min.value
 }
 
 // WalkFunc is a walker function type
-type WalkFunc[Key constraints.Ordered, Value any] func(key Key,
+type WalkFunc[Key, Value any] func(key Key,
 	value Value) error
 
 // ErrStop is the error for stop walking
 var ErrStop = errors.New("stop a walking")
 
-func (n *node[Key, Value]) walkLeft(sentinel *node[Key, Value], from, to Key,
+func (t *Tree[Key, Value]) walkLeft(n *node[Key, Value], from, to Key,
 	walkFunc WalkFunc[Key, Value]) (err error) {
 
-	if n.key > from {
-		if n.left != sentinel {
-			if err = n.left.walkLeft(sentinel, from, to, walkFunc); err != nil {
+	var cf, ct = t.cmp(n.key, from), t.cmp(n.key, to)
+
+	if cf > 0 {
+		if n.left != t.sentinel {
+			if err = t.walkLeft(n.left, from, to, walkFunc); err != nil {
 				return
 			}
 		}
 	}
 
-	if n.key >= from && n.key <= to {
+	if cf >= 0 && ct <= 0 {
 		if err = walkFunc(n.key, n.value); err != nil {
 			return
 		}
 	}
 
-	if n.key < to {
-		if n.right != sentinel {
-			err = n.right.walkLeft(sentinel, from, to, walkFunc)
+	if ct < 0 {
+		if n.right != t.sentinel {
+			err = t.walkLeft(n.right, from, to, walkFunc)
 			if err != nil {
 				return
 			}
@@ -501,27 +801,29 @@ func (n *node[Key, Value]) walkLeft(sentinel *node[Key, Value], from, to Key,
 	return // nil
 }
 
-func (n *node[Key, Value]) walkRight(sentinel *node[Key, Value], from, to Key,
+func (t *Tree[Key, Value]) walkRight(n *node[Key, Value], from, to Key,
 	walkFunc WalkFunc[Key, Value]) (err error) {
 
-	if n.key < from {
-		if n.right != sentinel {
-			err = n.right.walkRight(sentinel, from, to, walkFunc)
+	var cf, ct = t.cmp(n.key, from), t.cmp(n.key, to)
+
+	if cf < 0 {
+		if n.right != t.sentinel {
+			err = t.walkRight(n.right, from, to, walkFunc)
 			if err != nil {
 				return
 			}
 		}
 	}
 
-	if n.key <= from && n.key >= to {
+	if cf <= 0 && ct >= 0 {
 		if err = walkFunc(n.key, n.value); err != nil {
 			return
 		}
 	}
 
-	if n.key > to {
-		if n.left != sentinel {
-			err = n.left.walkRight(sentinel, from, to, walkFunc)
+	if ct > 0 {
+		if n.left != t.sentinel {
+			err = t.walkRight(n.left, from, to, walkFunc)
 			if err != nil {
 				return
 			}
@@ -546,23 +848,34 @@ func (n *node[Key, Value]) walkRight(sentinel *node[Key, Value], from, to Key,
 //
 //    tr.Walk(math.MinUint, math.MaxUint, walkFunc)
 //
-// The Tree shouldn't be modified inside the WalkFunc.
+// The Tree shouldn't be modified inside the WalkFunc. Use the WalkMut
+// to change values or to delete keys while walking.
 func (t *Tree[Key, Value]) Walk(from, to Key,
 	walkFunc WalkFunc[Key, Value]) (err error) {
 
-	switch {
-	case from == to:
+	if t.root == t.sentinel {
+		return // empty
+	}
+
+	switch c := t.cmp(from, to); {
+	case c == 0:
 		var node = t.findNode(from)
 		if node != t.sentinel {
 			return walkFunc(node.key, node.value)
 		}
 		return
-	case from < to:
-		return t.root.walkLeft(t.sentinel, from, to, walkFunc)
+	case c < 0:
+		if t.ordered != nil {
+			return t.ordered.walkLeft(t, t.root, from, to, walkFunc)
+		}
+		return t.walkLeft(t.root, from, to, walkFunc)
 	default: // to < from
 	}
 
-	return t.root.walkRight(t.sentinel, from, to, walkFunc)
+	if t.ordered != nil {
+		return t.ordered.walkRight(t, t.root, from, to, walkFunc)
+	}
+	return t.walkRight(t.root, from, to, walkFunc)
 }
 
 // Slice returns all values at given range if any.
@@ -36,8 +36,14 @@
 package rbtree
 
 import (
+	"bytes"
+	"cmp"
+	"errors"
 	"math"
+	"math/rand"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -164,3 +170,171 @@ func TestTree(t *testing.T) {
 	tr.Del(2)
 	tr.Del(3)
 }
+
+func TestTree_neighbours(t *testing.T) {
+
+	var tr = New[int, string]()
+
+	var _, _, ok = tr.Floor(0)
+	assert.False(t, ok)
+	_, _, ok = tr.Ceiling(0)
+	assert.False(t, ok)
+	_, _, ok = tr.Lower(0)
+	assert.False(t, ok)
+	_, _, ok = tr.Higher(0)
+	assert.False(t, ok)
+
+	tr.Set(10, "ten")
+	tr.Set(20, "twenty")
+
+	var key, value, _ = tr.Floor(15)
+	assert.Equal(t, 10, key)
+	assert.Equal(t, "ten", value)
+	key, _, _ = tr.Floor(20)
+	assert.Equal(t, 20, key)
+	_, _, ok = tr.Floor(9)
+	assert.False(t, ok)
+
+	key, value, _ = tr.Ceiling(15)
+	assert.Equal(t, 20, key)
+	assert.Equal(t, "twenty", value)
+	key, _, _ = tr.Ceiling(10)
+	assert.Equal(t, 10, key)
+	_, _, ok = tr.Ceiling(21)
+	assert.False(t, ok)
+
+	key, _, _ = tr.Lower(20)
+	assert.Equal(t, 10, key)
+	_, _, ok = tr.Lower(10)
+	assert.False(t, ok)
+
+	key, _, _ = tr.Higher(10)
+	assert.Equal(t, 20, key)
+	_, _, ok = tr.Higher(20)
+	assert.False(t, ok)
+}
+
+// checkTree checks the red-black properties, parent links, subtree sizes,
+// order of keys and length of the tree.
+func checkTree[Key, Value any](t *testing.T,
+	tr *Tree[Key, Value]) {
+
+	t.Helper()
+
+	var check func(n, parent *node[Key, Value]) (size, blackHeight int)
+	check = func(n, parent *node[Key, Value]) (size, blackHeight int) {
+		if n == tr.sentinel {
+			return 0, 1
+		}
+		// parents of shared nodes can be wrong, and ancestors of owned
+		// nodes are owned
+		var owned = n.gen == tr.gen.Load()
+		if owned || !tr.stale {
+			assert.True(t, n.parent == parent, "wrong parent link")
+		}
+		if owned && parent != nil {
+			assert.Equal(t, n.gen, parent.gen, "owned parent")
+		}
+		if n.color == red {
+			assert.Equal(t, black, n.left.color, "red-red")
+			assert.Equal(t, black, n.right.color, "red-red")
+		}
+		if n.left != tr.sentinel {
+			assert.Less(t, tr.cmp(n.left.key, n.key), 0, "order")
+		}
+		if n.right != tr.sentinel {
+			assert.Greater(t, tr.cmp(n.right.key, n.key), 0, "order")
+		}
+		var ls, lh = check(n.left, n)
+		var rs, rh = check(n.right, n)
+		assert.Equal(t, lh, rh, "black height")
+		size = ls + rs + 1
+		assert.Equal(t, size, n.size, "size")
+		if tr.augment != nil {
+			var c = *n // can be shared
+			tr.augment(&c)
+			assert.Equal(t, n.value, c.value, "augmented")
+		}
+		if n.color == black {
+			lh++
+		}
+		return size, lh
+	}
+
+	assert.Equal(t, black, tr.root.color, "root color")
+	assert.Equal(t, black, tr.sentinel.color, "sentinel color")
+	assert.Zero(t, tr.sentinel.size, "sentinel size")
+	var size, _ = check(tr.root, nil)
+	assert.Equal(t, tr.len, size, "len")
+	assert.True(t, tr.min == tr.minimum(tr.root), "cached min")
+	assert.True(t, tr.max == tr.maximum(tr.root), "cached max")
+}
+
+func TestNewFunc(t *testing.T) {
+
+	// case-insensitive strings
+	var tr = NewFunc[string, int](func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	assert.True(t, tr.Set("Hello", 1))
+	assert.False(t, tr.Set("HELLO", 2))
+	assert.True(t, tr.Set("world", 3))
+	assert.True(t, tr.Set("abc", 4))
+	assert.Equal(t, 3, tr.Len())
+	assert.Equal(t, 2, tr.Get("hello"))
+	assert.Equal(t, []string{"abc", "Hello", "world"},
+		tr.SliceKeys("A", "Z"))
+	assert.Equal(t, []int{3, 2}, tr.Slice("WORLD", "b"))
+	assert.True(t, tr.Move("WORLD", "zzz"))
+	assert.Equal(t, 3, tr.Get("ZZZ"))
+	assert.True(t, tr.Del("ABC"))
+	checkTree(t, tr)
+
+	// byte slices
+	var bt = NewFunc[[]byte, string](bytes.Compare)
+	bt.Set([]byte("b"), "b")
+	bt.Set([]byte("a"), "a")
+	bt.Set([]byte("c"), "c")
+	assert.True(t, bt.IsExist([]byte("a")))
+	assert.Equal(t, []string{"a", "b"}, bt.Slice([]byte("a"), []byte("b")))
+	var key, _ = bt.Max()
+	assert.Equal(t, []byte("c"), key)
+
+	// composite keys
+	type point struct{ x, y int }
+	var pt = NewFunc[point, string](func(a, b point) int {
+		if c := cmp.Compare(a.x, b.x); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.y, b.y)
+	})
+	pt.Set(point{1, 2}, "1:2")
+	pt.Set(point{1, 1}, "1:1")
+	pt.Set(point{0, 9}, "0:9")
+	assert.Equal(t, []string{"0:9", "1:1", "1:2"},
+		pt.Slice(point{math.MinInt, 0}, point{math.MaxInt, 0}))
+
+	// time
+	var (
+		now = time.Now()
+		tt  = NewFunc[time.Time, int](time.Time.Compare)
+	)
+	for i := 0; i < 100; i++ {
+		tt.Set(now.Add(time.Duration(rand.Intn(1000))*time.Second), i)
+	}
+	checkTree(t, tt)
+	var prev time.Time
+	for key := range tt.Keys() {
+		assert.True(t, key.After(prev))
+		prev = key
+	}
+}
+
+func TestTree_emptyWalk(t *testing.T) {
+	var tr = New[int, string]()
+	var err = tr.Walk(math.MinInt, math.MaxInt, func(int, string) error {
+		return errors.New("unexpected call")
+	})
+	assert.NoError(t, err)
+	assert.Nil(t, tr.Slice(math.MaxInt, math.MinInt))
+}
@@ -1,12 +1,14 @@
 package rbtree
 
 import (
+	"io"
+	"iter"
 	"sync"
 
 	"golang.org/x/exp/constraints"
 )
 
-type TreeThreadSafe[Key constraints.Ordered, Value any] struct {
+type TreeThreadSafe[Key, Value any] struct {
 	mx   sync.RWMutex
 	tree *Tree[Key, Value]
 }
@@ -21,8 +23,19 @@ func NewThreadSafe[Key constraints.Ordered, Value any]() (
 	return
 }
 
+// NewThreadSafeFunc creates the new thread-safe RB-Tree that uses given
+// function to compare keys. See NewFunc for details.
+func NewThreadSafeFunc[Key, Value any](cmp CompareFunc[Key]) (
+	tts *TreeThreadSafe[Key, Value]) {
+
+	tts = &TreeThreadSafe[Key, Value]{
+		tree: NewFunc[Key, Value](cmp),
+	}
+	return
+}
+
 // ToThreadSafe wraps a Tree.
-func ToThreadSafe[Key constraints.Ordered, Value any](tree *Tree[Key, Value]) (
+func ToThreadSafe[Key, Value any](tree *Tree[Key, Value]) (
 	tts *TreeThreadSafe[Key, Value]) {
 
 	tts = &TreeThreadSafe[Key, Value]{
@@ -39,6 +52,57 @@ func (t *TreeThreadSafe[Key, Value]) Tree() (tree *Tree[Key, Value]) {
 	return t.tree
 }
 
+// Update calls given function with the underlying Tree under the write
+// lock. If the function returns an error or panics, then all changes made
+// by the function are rolled back. The Tree can't be used after the
+// function returns, and methods of the TreeThreadSafe can't be called
+// inside the function.
+//
+//	err = tts.Update(func(tx *rbtree.Tree[string, int]) error {
+//	    if tx.IsExist("a") {
+//	        return errExists // nothing changed
+//	    }
+//	    tx.Set("b", 1)
+//	    tx.Del("c")
+//	    return nil
+//	})
+//
+// Changes of other trees, such as a right tree of Join, are not rolled
+// back. A rollback takes O(klogn), where k is number of changes.
+func (t *TreeThreadSafe[Key, Value]) Update(
+	fn func(tx *Tree[Key, Value]) error) (err error) {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.tree.begin()
+	var ok bool
+	defer func() {
+		if !ok {
+			t.tree.rollback() // panic
+		}
+	}()
+
+	if err = fn(t.tree); err != nil {
+		t.tree.rollback()
+	} else {
+		t.tree.commit()
+	}
+	ok = true
+	return
+}
+
+// View calls given function with the underlying Tree under the read lock.
+// The Tree can't be used after the function returns.
+func (t *TreeThreadSafe[Key, Value]) View(
+	fn func(tx ReadOnlyTree[Key, Value]) error) error {
+
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	return fn(t.tree)
+}
+
 // Set the value. O(logn). This will overwrite the existing value.
 func (t *TreeThreadSafe[Key, Value]) Set(key Key, value Value) (added bool) {
 	t.mx.Lock()
@@ -88,6 +152,70 @@ func (t *TreeThreadSafe[Key, Value]) IsExist(key Key) bool {
 	return t.tree.IsExist(key)
 }
 
+// Floor returns the greatest key less than or equal to the given one and
+// its value O(logn). It returns false, if there is no such key.
+func (t *TreeThreadSafe[Key, Value]) Floor(key Key) (Key, Value, bool) {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	return t.tree.Floor(key)
+}
+
+// Ceiling returns the smallest key greater than or equal to the given one
+// and its value O(logn). It returns false, if there is no such key.
+func (t *TreeThreadSafe[Key, Value]) Ceiling(key Key) (Key, Value, bool) {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	return t.tree.Ceiling(key)
+}
+
+// Lower returns the greatest key strictly less than the given one and
+// its value O(logn). It returns false, if there is no such key.
+func (t *TreeThreadSafe[Key, Value]) Lower(key Key) (Key, Value, bool) {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	return t.tree.Lower(key)
+}
+
+// Higher returns the smallest key strictly greater than the given one and
+// its value O(logn). It returns false, if there is no such key.
+func (t *TreeThreadSafe[Key, Value]) Higher(key Key) (Key, Value, bool) {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	return t.tree.Higher(key)
+}
+
+// Rank returns number of keys less than the given one O(logn). It is
+// position of the key in the Tree, if the key exists.
+func (t *TreeThreadSafe[Key, Value]) Rank(key Key) int {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	return t.tree.Rank(key)
+}
+
+// Select returns i-th key in ascending order and its value O(logn).
+// The i starts from zero. It returns false, if the i is out of range.
+func (t *TreeThreadSafe[Key, Value]) Select(i int) (Key, Value, bool) {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	return t.tree.Select(i)
+}
+
+// CountRange returns number of keys at given range O(logn). The range
+// is inclusive, and the from can be greater than the to, the same way as
+// for the Walk.
+func (t *TreeThreadSafe[Key, Value]) CountRange(from, to Key) int {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	return t.tree.CountRange(from, to)
+}
+
 // Len O(1)
 func (t *TreeThreadSafe[Key, Value]) Len() int {
 	t.mx.RLock()
@@ -113,7 +241,7 @@ func (t *TreeThreadSafe[Key, Value]) Empty() {
 	t.tree.Empty()
 }
 
-// Max returns maximum index and its value O(logn)
+// Max returns maximum index and its value O(1)
 func (t *TreeThreadSafe[Key, Value]) Max() (Key, Value) {
 	t.mx.RLock()
 	defer t.mx.RUnlock()
@@ -121,7 +249,7 @@ func (t *TreeThreadSafe[Key, Value]) Max() (Key, Value) {
 	return t.tree.Max()
 }
 
-// Min returns minimum indexed and its value O(logn)
+// Min returns minimum indexed and its value O(1)
 func (t *TreeThreadSafe[Key, Value]) Min() (Key, Value) {
 	t.mx.RLock()
 	defer t.mx.RUnlock()
@@ -169,3 +297,358 @@ func (t *TreeThreadSafe[Key, Value]) SliceKeys(from, to Key) (keys []Key) {
 
 	return t.tree.SliceKeys(from, to)
 }
+
+// All returns an iterator over all key-value pairs of the Tree in
+// ascending order of keys.
+//
+// The read lock is acquired when the iteration starts and it is held
+// until the loop ends or breaks. Thus, a long loop blocks all writers.
+// No method of the TreeThreadSafe may be called inside the loop, neither
+// write nor read one. A write method deadlocks at once, and a read method
+// deadlocks as soon as a writer is waiting for the lock, because the read
+// lock is not recursive. Collect keys inside the loop and use them after
+// it, or use the Slice.
+func (t *TreeThreadSafe[Key, Value]) All() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		t.mx.RLock()
+		defer t.mx.RUnlock()
+
+		t.tree.All()(yield)
+	}
+}
+
+// Backward returns an iterator over all key-value pairs of the Tree in
+// descending order of keys. The read lock is held during the loop,
+// see All for details.
+func (t *TreeThreadSafe[Key, Value]) Backward() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		t.mx.RLock()
+		defer t.mx.RUnlock()
+
+		t.tree.Backward()(yield)
+	}
+}
+
+// Range returns an iterator over key-value pairs of the Tree at given
+// range, in the same order as Walk uses. The read lock is held during
+// the loop, see All for details.
+func (t *TreeThreadSafe[Key, Value]) Range(from, to Key) iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		t.mx.RLock()
+		defer t.mx.RUnlock()
+
+		t.tree.Range(from, to)(yield)
+	}
+}
+
+// Keys returns an iterator over all keys of the Tree in ascending order.
+// The read lock is held during the loop, see All for details.
+func (t *TreeThreadSafe[Key, Value]) Keys() iter.Seq[Key] {
+	return func(yield func(Key) bool) {
+		t.mx.RLock()
+		defer t.mx.RUnlock()
+
+		t.tree.Keys()(yield)
+	}
+}
+
+// Values returns an iterator over all values of the Tree in ascending
+// order of keys. The read lock is held during the loop, see All
+// for details.
+func (t *TreeThreadSafe[Key, Value]) Values() iter.Seq[Value] {
+	return func(yield func(Value) bool) {
+		t.mx.RLock()
+		defer t.mx.RUnlock()
+
+		t.tree.Values()(yield)
+	}
+}
+
+// Encode writes the Tree to the w using given codecs of keys and values.
+// It returns number of bytes written.
+func (t *TreeThreadSafe[Key, Value]) Encode(w io.Writer, keyCodec Codec[Key],
+	valueCodec Codec[Value]) (n int64, err error) {
+
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	return t.encoded().Encode(w, keyCodec, valueCodec)
+}
+
+// Decode replaces content of the Tree with data read from the r using
+// given codecs of keys and values. It returns number of bytes read.
+func (t *TreeThreadSafe[Key, Value]) Decode(r io.Reader, keyCodec Codec[Key],
+	valueCodec Codec[Value]) (n int64, err error) {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.lazyInit()
+	return t.tree.Decode(r, keyCodec, valueCodec)
+}
+
+// WriteTo writes the Tree to the w using default codecs. It implements
+// io.WriterTo interface.
+func (t *TreeThreadSafe[Key, Value]) WriteTo(w io.Writer) (n int64,
+	err error) {
+
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	return t.encoded().WriteTo(w)
+}
+
+// ReadFrom replaces content of the Tree with data read from the r using
+// default codecs. It implements io.ReaderFrom interface.
+func (t *TreeThreadSafe[Key, Value]) ReadFrom(r io.Reader) (n int64,
+	err error) {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.lazyInit()
+	return t.tree.ReadFrom(r)
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler interface.
+func (t *TreeThreadSafe[Key, Value]) MarshalBinary() (data []byte,
+	err error) {
+
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	return t.encoded().MarshalBinary()
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler interface.
+func (t *TreeThreadSafe[Key, Value]) UnmarshalBinary(data []byte) error {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.lazyInit()
+	return t.tree.UnmarshalBinary(data)
+}
+
+// lazyInit initializes a zero TreeThreadSafe, for example, created by
+// a decoder. The Tree itself is initialized by its methods.
+func (t *TreeThreadSafe[Key, Value]) lazyInit() {
+	if t.tree == nil {
+		t.tree = new(Tree[Key, Value])
+	}
+}
+
+// encoded returns the Tree to encode. A zero TreeThreadSafe has no Tree, and
+// it's encoded as an empty one.
+func (t *TreeThreadSafe[Key, Value]) encoded() *Tree[Key, Value] {
+	if t.tree == nil {
+		return new(Tree[Key, Value])
+	}
+	return t.tree
+}
+
+// MarshalJSON implements json.Marshaler interface.
+func (t *TreeThreadSafe[Key, Value]) MarshalJSON() ([]byte, error) {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	return t.encoded().MarshalJSON()
+}
+
+// UnmarshalJSON implements json.Unmarshaler interface.
+func (t *TreeThreadSafe[Key, Value]) UnmarshalJSON(data []byte) error {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.lazyInit()
+	return t.tree.UnmarshalJSON(data)
+}
+
+// GobEncode implements gob.GobEncoder interface.
+func (t *TreeThreadSafe[Key, Value]) GobEncode() ([]byte, error) {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	return t.encoded().GobEncode()
+}
+
+// GobDecode implements gob.GobDecoder interface.
+func (t *TreeThreadSafe[Key, Value]) GobDecode(data []byte) error {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	t.lazyInit()
+	return t.tree.GobDecode(data)
+}
+
+// Clone returns an independent copy of the Tree O(1). See Tree.Clone
+// for details.
+func (t *TreeThreadSafe[Key, Value]) Clone() *TreeThreadSafe[Key, Value] {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	return &TreeThreadSafe[Key, Value]{tree: t.tree.Clone()}
+}
+
+// CompareAndSwap swaps the old and new values for the key, if the value
+// stored in the Tree is equal to the old one O(logn). See Tree.CompareAndSwap.
+func (t *TreeThreadSafe[Key, Value]) CompareAndSwap(key Key,
+	old, new Value) (swapped bool) {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	return t.tree.CompareAndSwap(key, old, new)
+}
+
+// CompareAndSwapFunc is like the CompareAndSwap, but it uses given
+// function to compare the values.
+func (t *TreeThreadSafe[Key, Value]) CompareAndSwapFunc(key Key,
+	old, new Value, eq EqualFunc[Value]) (swapped bool) {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	return t.tree.CompareAndSwapFunc(key, old, new, eq)
+}
+
+// CompareAndDelete deletes the key, if its value is equal to the old one
+// O(logn). See Tree.CompareAndDelete.
+func (t *TreeThreadSafe[Key, Value]) CompareAndDelete(key Key,
+	old Value) (deleted bool) {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	return t.tree.CompareAndDelete(key, old)
+}
+
+// CompareAndDeleteFunc is like the CompareAndDelete, but it uses given
+// function to compare the values.
+func (t *TreeThreadSafe[Key, Value]) CompareAndDeleteFunc(key Key,
+	old Value, eq EqualFunc[Value]) (deleted bool) {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	return t.tree.CompareAndDeleteFunc(key, old, eq)
+}
+
+// Upsert sets value returned by given function for the key O(logn). The
+// function is called under the write lock, see Tree.Upsert.
+func (t *TreeThreadSafe[Key, Value]) Upsert(key Key,
+	fn func(old Value, exists bool) Value) (added bool) {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	return t.tree.Upsert(key, fn)
+}
+
+// GetOrSet returns existing value for the key O(logn). Otherwise, it sets
+// and returns given value. The loaded is true, if the value exists.
+func (t *TreeThreadSafe[Key, Value]) GetOrSet(key Key,
+	value Value) (actual Value, loaded bool) {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	return t.tree.GetOrSet(key, value)
+}
+
+// Swap sets the value for the key and returns the previous value O(logn).
+// The existed is false, if the key is added.
+func (t *TreeThreadSafe[Key, Value]) Swap(key Key,
+	value Value) (old Value, existed bool) {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	return t.tree.Swap(key, value)
+}
+
+// LoadAndDelete deletes the key and returns its value O(logn). The loaded
+// is false, if the key doesn't exist.
+func (t *TreeThreadSafe[Key, Value]) LoadAndDelete(key Key) (value Value,
+	loaded bool) {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	return t.tree.LoadAndDelete(key)
+}
+
+// MoveEx is like the Move, but it also returns previous value of the
+// newKey. See Tree.MoveEx.
+func (t *TreeThreadSafe[Key, Value]) MoveEx(oldKey, newKey Key) (
+	displaced Value, overwritten, moved bool) {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	return t.tree.MoveEx(oldKey, newKey)
+}
+
+// DeleteRange deletes all keys in given inclusive range O(logn) and
+// returns number of deleted keys.
+func (t *TreeThreadSafe[Key, Value]) DeleteRange(from, to Key) (deleted int) {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	return t.tree.DeleteRange(from, to)
+}
+
+// DeleteFunc deletes all keys for which given function returns true and
+// returns number of deleted keys. The function is called under the write
+// lock, see Tree.DeleteFunc.
+func (t *TreeThreadSafe[Key, Value]) DeleteFunc(del func(key Key,
+	value Value) bool) (deleted int) {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	return t.tree.DeleteFunc(del)
+}
+
+// WalkMut walks on the Tree under the write lock, given function can
+// change values and delete keys. See Tree.WalkMut for details.
+func (t *TreeThreadSafe[Key, Value]) WalkMut(from, to Key,
+	walkFunc WalkMutFunc[Key, Value]) error {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	return t.tree.WalkMut(from, to, walkFunc)
+}
+
+// PopMin deletes the minimum key and returns it with its value O(logn).
+// It returns false, if the Tree is empty.
+func (t *TreeThreadSafe[Key, Value]) PopMin() (key Key, value Value,
+	ok bool) {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	return t.tree.PopMin()
+}
+
+// PopMax deletes the maximum key and returns it with its value O(logn).
+// It returns false, if the Tree is empty.
+func (t *TreeThreadSafe[Key, Value]) PopMax() (key Key, value Value,
+	ok bool) {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	return t.tree.PopMax()
+}
+
+// PopMinN deletes up to n minimum keys and returns them with values in
+// ascending order O(logn + n).
+func (t *TreeThreadSafe[Key, Value]) PopMinN(n int) (keys []Key,
+	values []Value) {
+
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	return t.tree.PopMinN(n)
+}
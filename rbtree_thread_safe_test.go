@@ -36,7 +36,11 @@
 package rbtree
 
 import (
+	"bytes"
+	"errors"
 	"math"
+	"math/rand"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -164,3 +168,352 @@ func TestTreeThreadSafe(t *testing.T) {
 	tr.Del(2)
 	tr.Del(3)
 }
+
+func TestTreeThreadSafe_All(t *testing.T) {
+
+	var tr = NewThreadSafe[int, string]()
+	tr.Set(1, "one")
+	tr.Set(2, "two")
+	tr.Set(3, "three")
+
+	var keys, vals = collect(tr.All())
+	assert.Equal(t, []int{1, 2, 3}, keys)
+	assert.Equal(t, []string{"one", "two", "three"}, vals)
+
+	keys, _ = collect(tr.Backward())
+	assert.Equal(t, []int{3, 2, 1}, keys)
+
+	keys, _ = collect(tr.Range(3, 2))
+	assert.Equal(t, []int{3, 2}, keys)
+
+	keys = keys[:0]
+	for k := range tr.Keys() {
+		keys = append(keys, k)
+	}
+	assert.Equal(t, []int{1, 2, 3}, keys)
+
+	vals = vals[:0]
+	for v := range tr.Values() {
+		vals = append(vals, v)
+	}
+	assert.Equal(t, []string{"one", "two", "three"}, vals)
+}
+
+func TestTreeThreadSafe_iterationLock(t *testing.T) {
+
+	var tr = NewThreadSafe[int, string]()
+	tr.Set(1, "one")
+	tr.Set(2, "two")
+
+	// not locked before the loop starts
+	var seq = tr.All()
+	assert.True(t, tr.mx.TryLock())
+	tr.mx.Unlock()
+
+	for range seq {
+		// writers are blocked
+		assert.False(t, tr.mx.TryLock())
+		break
+	}
+
+	// released after break
+	assert.True(t, tr.mx.TryLock())
+	tr.mx.Unlock()
+
+	for range tr.Range(1, 2) {
+		assert.False(t, tr.mx.TryLock())
+	}
+
+	// released after the end
+	assert.True(t, tr.mx.TryLock())
+	tr.mx.Unlock()
+}
+
+func TestTreeThreadSafe_neighbours(t *testing.T) {
+
+	var tr = NewThreadSafe[int, string]()
+	tr.Set(10, "ten")
+	tr.Set(20, "twenty")
+
+	var key, value, ok = tr.Floor(15)
+	assert.True(t, ok)
+	assert.Equal(t, 10, key)
+	assert.Equal(t, "ten", value)
+
+	key, _, _ = tr.Ceiling(15)
+	assert.Equal(t, 20, key)
+	key, _, _ = tr.Lower(20)
+	assert.Equal(t, 10, key)
+	key, _, _ = tr.Higher(10)
+	assert.Equal(t, 20, key)
+	_, _, ok = tr.Higher(20)
+	assert.False(t, ok)
+}
+
+func TestTreeThreadSafe_Rank(t *testing.T) {
+
+	var tr = NewThreadSafe[int, string]()
+	tr.Set(10, "ten")
+	tr.Set(20, "twenty")
+	tr.Set(30, "thirty")
+
+	assert.Equal(t, 1, tr.Rank(20))
+	var key, value, ok = tr.Select(2)
+	assert.True(t, ok)
+	assert.Equal(t, 30, key)
+	assert.Equal(t, "thirty", value)
+	assert.Equal(t, 2, tr.CountRange(25, 5))
+}
+
+func TestNewThreadSafeFunc(t *testing.T) {
+
+	var tr = NewThreadSafeFunc[[]byte, int](bytes.Compare)
+	assert.True(t, tr.Set([]byte("b"), 2))
+	assert.True(t, tr.Set([]byte("a"), 1))
+	assert.False(t, tr.SetNx([]byte("a"), 0))
+	assert.Equal(t, 1, tr.Get([]byte("a")))
+	assert.Equal(t, []int{2, 1}, tr.Slice([]byte("z"), []byte("")))
+	checkTree(t, tr.Tree())
+}
+
+func TestTreeThreadSafe_Clone(t *testing.T) {
+	var tr = NewThreadSafe[int, int]()
+	tr.Set(1, 1)
+	var c = tr.Clone()
+	c.Set(2, 2)
+	assert.Equal(t, 1, tr.Len())
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestTreeThreadSafe_Update(t *testing.T) {
+
+	var (
+		tts    = NewThreadSafe[int, int]()
+		errTx  = errors.New("tx")
+		before []int
+	)
+	for i := 0; i < 100; i++ {
+		tts.Set(i, i)
+	}
+	before = tts.Slice(0, 1000)
+
+	var err = tts.Update(func(tx *Tree[int, int]) error {
+		tx.Set(1, 100)  // overwrite
+		tx.Set(200, 0)  // add
+		tx.Del(2)       // delete
+		tx.Move(3, 300) // move
+		tx.SetNx(4, 400)
+		return errTx
+	})
+	assert.ErrorIs(t, err, errTx)
+	assert.Equal(t, before, tts.Slice(0, 1000))
+	checkTree(t, tts.tree)
+
+	// bulk changes
+	err = tts.Update(func(tx *Tree[int, int]) error {
+		tx.Set(500, 500)
+		var left, right = tx.Split(50)
+		left.Set(-1, -1)
+		var joined, _ = Join(left, right)
+		tx.UnionWith(joined, nil)
+		tx.Empty()
+		tx.Set(1000, 1000)
+		var other = New[int, int]()
+		other.Set(2000, 2000)
+		Join(tx, other)
+		return errTx
+	})
+	assert.ErrorIs(t, err, errTx)
+	assert.Equal(t, before, tts.Slice(0, 1000))
+	assert.Equal(t, 100, tts.Len())
+	checkTree(t, tts.tree)
+
+	// panic
+	assert.Panics(t, func() {
+		tts.Update(func(tx *Tree[int, int]) error {
+			tx.Del(10)
+			panic("tx")
+		})
+	})
+	assert.Equal(t, before, tts.Slice(0, 1000))
+
+	// commit
+	err = tts.Update(func(tx *Tree[int, int]) error {
+		if tx.IsExist(1) {
+			tx.Set(200, 200)
+			tx.Del(1)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 100, tts.Len())
+	assert.False(t, tts.IsExist(1))
+	assert.Equal(t, 200, tts.Get(200))
+	assert.Nil(t, tts.tree.journal)
+}
+
+func TestTreeThreadSafe_updateOwnership(t *testing.T) {
+
+	// notOwned returns number of nodes, that the tr doesn't own
+	var notOwned = func(tr *Tree[int, int]) (n int) {
+		var walk func(x *node[int, int])
+		walk = func(x *node[int, int]) {
+			if x == tr.sentinel {
+				return
+			}
+			if x.gen != tr.gen.Load() {
+				n++
+			}
+			walk(x.left)
+			walk(x.right)
+		}
+		walk(tr.root)
+		return
+	}
+
+	for _, tt := range []struct {
+		name string
+		tx   func(tx *Tree[int, int])
+	}{
+		{"delete range", func(tx *Tree[int, int]) { tx.DeleteRange(10, 20) }},
+		{"pop min n", func(tx *Tree[int, int]) { tx.PopMinN(10) }},
+		{"pop all", func(tx *Tree[int, int]) { tx.PopMinN(1000) }},
+		{"empty", func(tx *Tree[int, int]) { tx.Empty() }},
+		{"load", func(tx *Tree[int, int]) {
+			tx.UnmarshalJSON([]byte(`[{"k":1,"v":1},{"k":2,"v":2}]`))
+		}},
+		{"split", func(tx *Tree[int, int]) {
+			var left, right = tx.Split(50)
+			var joined, _ = Join(left, right)
+			tx.UnionWith(joined, nil)
+		}},
+	} {
+		var tts = NewThreadSafe[int, int]()
+		for i := 0; i < 100; i++ {
+			tts.Set(i, i)
+		}
+		var before = tts.Slice(0, 1000)
+
+		// rollback
+		tts.Update(func(tx *Tree[int, int]) error {
+			tt.tx(tx)
+			tx.Set(1000, 1000)
+			return ErrStop
+		})
+		assert.Equal(t, before, tts.Slice(0, 1000), tt.name)
+		checkTree(t, tts.tree)
+
+		// commit
+		assert.NoError(t, tts.Update(func(tx *Tree[int, int]) error {
+			tt.tx(tx)
+			tx.Set(1000, 1000)
+			return nil
+		}))
+		checkTree(t, tts.tree)
+		assert.False(t, tts.tree.stale, tt.name)
+		assert.Zero(t, notOwned(tts.tree), tt.name)
+	}
+}
+
+func TestTreeThreadSafe_randomRollback(t *testing.T) {
+
+	var (
+		rnd   = rand.New(rand.NewSource(42))
+		tts   = NewThreadSafe[int, int]()
+		errTx = errors.New("tx")
+	)
+
+	for i := 0; i < 100; i++ {
+		var before = tts.tree.Clone()
+		var commit = rnd.Intn(2) == 0
+		tts.Update(func(tx *Tree[int, int]) error {
+			for j := rnd.Intn(50); j >= 0; j-- {
+				var key = rnd.Intn(100)
+				switch rnd.Intn(6) {
+				case 0:
+					tx.Del(key)
+				case 1:
+					tx.Move(key, rnd.Intn(100))
+				case 2:
+					tx.DeleteRange(key, key+rnd.Intn(10))
+				case 3:
+					tx.PopMinN(rnd.Intn(3))
+				default:
+					tx.Set(key, rnd.Int())
+				}
+			}
+			if commit {
+				return nil
+			}
+			return errTx
+		})
+		checkTree(t, tts.tree)
+		if !commit {
+			assert.Equal(t, before.Slice(0, 100), tts.Slice(0, 100))
+			assert.Equal(t, before.SliceKeys(0, 100), tts.SliceKeys(0, 100))
+		}
+	}
+}
+
+func TestTreeThreadSafe_View(t *testing.T) {
+	var tts = NewThreadSafe[int, int]()
+	tts.Set(1, 1)
+	var err = tts.View(func(tx ReadOnlyTree[int, int]) error {
+		assert.Equal(t, 1, tx.Len())
+		assert.Equal(t, 1, tx.Get(1))
+		return ErrStop
+	})
+	assert.ErrorIs(t, err, ErrStop)
+}
+
+func TestTreeThreadSafe_Upsert(t *testing.T) {
+
+	var (
+		tts = NewThreadSafe[string, int]()
+		wg  sync.WaitGroup
+	)
+
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				tts.Upsert("upsert", func(old int, _ bool) int {
+					return old + 1
+				})
+				tts.GetOrSet("cas", 0)
+				for {
+					var old = tts.Get("cas")
+					if tts.CompareAndSwap("cas", old, old+1) {
+						break
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 800, tts.Get("upsert"))
+	assert.Equal(t, 800, tts.Get("cas"))
+	assert.True(t, tts.CompareAndSwapFunc("cas", 800, 0,
+		func(a, b int) bool { return a == b }))
+	assert.True(t, tts.CompareAndDeleteFunc("cas", 0,
+		func(a, b int) bool { return a == b }))
+	assert.True(t, tts.CompareAndDelete("upsert", 800))
+	assert.Equal(t, 0, tts.Len())
+}
+
+func TestTreeThreadSafe_Swap(t *testing.T) {
+
+	var tts = NewThreadSafe[int, int]()
+
+	var _, existed = tts.Swap(1, 1)
+	assert.False(t, existed)
+	var displaced, overwritten, moved = tts.MoveEx(1, 2)
+	assert.True(t, moved)
+	assert.False(t, overwritten)
+	assert.Zero(t, displaced)
+	var value, loaded = tts.LoadAndDelete(2)
+	assert.True(t, loaded)
+	assert.Equal(t, 1, value)
+}
@@ -0,0 +1,84 @@
+package rbtree
+
+import "iter"
+
+// All returns an iterator over all key-value pairs of the Tree in
+// ascending order of keys.
+//
+//	for key, value := range tr.All() {
+//	    fmt.Println(key, value)
+//	}
+//
+// The Tree shouldn't be modified inside the loop.
+func (t *Tree[Key, Value]) All() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		for n := t.minimum(t.root); n != t.sentinel; n = t.successor(n) {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over all key-value pairs of the Tree in
+// descending order of keys. The Tree shouldn't be modified inside the loop.
+func (t *Tree[Key, Value]) Backward() iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		for n := t.maximum(t.root); n != t.sentinel; n = t.predecessor(n) {
+			if !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Range returns an iterator over key-value pairs of the Tree at given
+// range. The range is inclusive and the order is the same as Walk uses,
+// e.g. if the from is greater than the to, then the order is descending.
+//
+//	for key, value := range tr.Range(10, 20) {
+//	    fmt.Println(key, value)
+//	}
+//
+// The Tree shouldn't be modified inside the loop.
+func (t *Tree[Key, Value]) Range(from, to Key) iter.Seq2[Key, Value] {
+	return func(yield func(Key, Value) bool) {
+		if t.cmp(from, to) <= 0 {
+			for n := t.ceilingNode(from); n != t.sentinel; n = t.successor(n) {
+				if t.cmp(n.key, to) > 0 || !yield(n.key, n.value) {
+					return
+				}
+			}
+			return
+		}
+		for n := t.floorNode(from); n != t.sentinel; n = t.predecessor(n) {
+			if t.cmp(n.key, to) < 0 || !yield(n.key, n.value) {
+				return
+			}
+		}
+	}
+}
+
+// Keys returns an iterator over all keys of the Tree in ascending order.
+// The Tree shouldn't be modified inside the loop.
+func (t *Tree[Key, Value]) Keys() iter.Seq[Key] {
+	return func(yield func(Key) bool) {
+		for n := t.minimum(t.root); n != t.sentinel; n = t.successor(n) {
+			if !yield(n.key) {
+				return
+			}
+		}
+	}
+}
+
+// Values returns an iterator over all values of the Tree in ascending
+// order of keys. The Tree shouldn't be modified inside the loop.
+func (t *Tree[Key, Value]) Values() iter.Seq[Value] {
+	return func(yield func(Value) bool) {
+		for n := t.minimum(t.root); n != t.sentinel; n = t.successor(n) {
+			if !yield(n.value) {
+				return
+			}
+		}
+	}
+}
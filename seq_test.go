@@ -0,0 +1,155 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collect[Key, Value any](seq func(func(Key, Value) bool)) (
+	keys []Key, vals []Value) {
+
+	for k, v := range seq {
+		keys = append(keys, k)
+		vals = append(vals, v)
+	}
+	return
+}
+
+func TestTree_All(t *testing.T) {
+
+	var tr = New[int, string]()
+
+	var keys, vals = collect(tr.All())
+	assert.Nil(t, keys)
+	assert.Nil(t, vals)
+
+	tr.Set(2, "two")
+	tr.Set(1, "one")
+	tr.Set(3, "three")
+
+	keys, vals = collect(tr.All())
+	assert.Equal(t, []int{1, 2, 3}, keys)
+	assert.Equal(t, []string{"one", "two", "three"}, vals)
+
+	keys, vals = collect(tr.Backward())
+	assert.Equal(t, []int{3, 2, 1}, keys)
+	assert.Equal(t, []string{"three", "two", "one"}, vals)
+
+	keys = keys[:0]
+	for k := range tr.Keys() {
+		keys = append(keys, k)
+	}
+	assert.Equal(t, []int{1, 2, 3}, keys)
+
+	vals = vals[:0]
+	for v := range tr.Values() {
+		vals = append(vals, v)
+	}
+	assert.Equal(t, []string{"one", "two", "three"}, vals)
+
+	// break
+	keys = keys[:0]
+	for k := range tr.All() {
+		keys = append(keys, k)
+		if k == 2 {
+			break
+		}
+	}
+	assert.Equal(t, []int{1, 2}, keys)
+}
+
+func TestTree_Range(t *testing.T) {
+
+	var tr = New[int, string]()
+	tr.Set(1, "one")
+	tr.Set(2, "two")
+	tr.Set(3, "three")
+
+	for _, tt := range []struct {
+		from, to int
+		keys     []int
+	}{
+		{math.MinInt, math.MaxInt, []int{1, 2, 3}},
+		{math.MaxInt, math.MinInt, []int{3, 2, 1}},
+		{1, 1, []int{1}},
+		{1, 2, []int{1, 2}},
+		{2, 1, []int{2, 1}},
+		{0, 4, []int{1, 2, 3}},
+		{5, 0, []int{3, 2, 1}},
+		{4, 4, nil},
+		{90, 210, nil},
+		{210, 90, nil},
+	} {
+		var keys, _ = collect(tr.Range(tt.from, tt.to))
+		assert.Equal(t, tt.keys, keys, "%d - %d", tt.from, tt.to)
+		assert.Equal(t, tt.keys, tr.SliceKeys(tt.from, tt.to))
+	}
+
+	var keys []int
+	for k := range tr.Range(3, 1) {
+		keys = append(keys, k)
+		break
+	}
+	assert.Equal(t, []int{3}, keys)
+}
+
+func Test_randomRange(t *testing.T) {
+
+	var (
+		tr   = New[int, int]()
+		keys []int
+	)
+
+	for i := 0; i < Count; i++ {
+		var k = rand.Intn(Count * 10)
+		if tr.Set(k, k) {
+			keys = append(keys, k)
+		}
+	}
+	sort.Ints(keys)
+
+	for i := 0; i < 100; i++ {
+		var from, to = rand.Intn(Count * 10), rand.Intn(Count * 10)
+		var got, _ = collect(tr.Range(from, to))
+		assert.Equal(t, tr.SliceKeys(from, to), got)
+	}
+}
@@ -0,0 +1,168 @@
+package rbtree
+
+import (
+	"iter"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Set is an ordered set of keys. It's a Tree with empty values, that take
+// no memory.
+type Set[Key any] struct {
+	tree *Tree[Key, struct{}]
+}
+
+// NewSet creates the new Set with given keys.
+func NewSet[Key constraints.Ordered](keys ...Key) *Set[Key] {
+	return newSetOf(New[Key, struct{}]()).add(keys)
+}
+
+// NewSetFunc creates the new Set with given keys, that uses given
+// function to compare keys. See also NewFunc.
+func NewSetFunc[Key any](cmp CompareFunc[Key], keys ...Key) *Set[Key] {
+	return newSetOf(NewFunc[Key, struct{}](cmp)).add(keys)
+}
+
+func newSetOf[Key any](tree *Tree[Key, struct{}]) *Set[Key] {
+	return &Set[Key]{tree: tree}
+}
+
+// add the keys and return the Set
+func (s *Set[Key]) add(keys []Key) *Set[Key] {
+	for _, key := range keys {
+		s.tree.Set(key, struct{}{})
+	}
+	return s
+}
+
+// Add the key O(logn). It returns false, if the key already exists.
+func (s *Set[Key]) Add(key Key) (added bool) {
+	return s.tree.SetNx(key, struct{}{})
+}
+
+// Remove the key O(logn). It returns false, if the key doesn't exist.
+func (s *Set[Key]) Remove(key Key) (removed bool) {
+	return s.tree.Del(key)
+}
+
+// Contains O(logn)
+func (s *Set[Key]) Contains(key Key) bool {
+	return s.tree.IsExist(key)
+}
+
+// Len O(1)
+func (s *Set[Key]) Len() int {
+	return s.tree.Len()
+}
+
+// Empty makes the set empty O(1).
+func (s *Set[Key]) Empty() {
+	s.tree.Empty()
+}
+
+// Clone returns an independent copy of the Set O(1), see Tree.Clone.
+func (s *Set[Key]) Clone() *Set[Key] {
+	return newSetOf(s.tree.Clone())
+}
+
+// Min returns minimum key O(1). It returns zero value for an empty set.
+func (s *Set[Key]) Min() (key Key) {
+	key, _ = s.tree.Min()
+	return
+}
+
+// Max returns maximum key O(1). It returns zero value for an empty set.
+func (s *Set[Key]) Max() (key Key) {
+	key, _ = s.tree.Max()
+	return
+}
+
+// Walk on the Set like the Tree.Walk does.
+func (s *Set[Key]) Walk(from, to Key, walkFunc func(key Key) error) error {
+	return s.tree.Walk(from, to, func(key Key, _ struct{}) error {
+		return walkFunc(key)
+	})
+}
+
+// Keys returns all keys at given range if any.
+func (s *Set[Key]) Keys(from, to Key) []Key {
+	return s.tree.SliceKeys(from, to)
+}
+
+// All returns an iterator over all keys of the Set in ascending order.
+func (s *Set[Key]) All() iter.Seq[Key] {
+	return s.tree.Keys()
+}
+
+// Range returns an iterator over keys of the Set at given range, see
+// Tree.Range.
+func (s *Set[Key]) Range(from, to Key) iter.Seq[Key] {
+	return func(yield func(Key) bool) {
+		for key := range s.tree.Range(from, to) {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// Union returns new set with keys of both sets O(n+m).
+func (s *Set[Key]) Union(other *Set[Key]) *Set[Key] {
+	return newSetOf(Union(s.tree, other.tree, nil))
+}
+
+// Intersect returns new set with keys, that exist in both sets O(n+m).
+func (s *Set[Key]) Intersect(other *Set[Key]) *Set[Key] {
+	return newSetOf(Intersect(s.tree, other.tree, nil))
+}
+
+// Difference returns new set with keys of the Set, that don't exist in
+// the other O(n+m).
+func (s *Set[Key]) Difference(other *Set[Key]) *Set[Key] {
+	return newSetOf(Difference(s.tree, other.tree))
+}
+
+// SymmetricDifference returns new set with keys, that exist only in one
+// of the sets O(n+m).
+func (s *Set[Key]) SymmetricDifference(other *Set[Key]) *Set[Key] {
+	return newSetOf(SymmetricDifference(s.tree, other.tree))
+}
+
+// Equal returns true, if both sets have the same keys O(n).
+func (s *Set[Key]) Equal(other *Set[Key]) bool {
+	return s.Len() == other.Len() && s.IsSubset(other)
+}
+
+// IsSubset returns true, if all keys of the Set exist in the other
+// O(n+m).
+func (s *Set[Key]) IsSubset(other *Set[Key]) bool {
+
+	if s.Len() > other.Len() {
+		return false
+	}
+
+	var (
+		a, b = s.tree, other.tree
+		x, y = a.min, b.min
+	)
+	for x != a.sentinel {
+		if y == b.sentinel {
+			return false
+		}
+		switch c := a.cmp(x.key, y.key); {
+		case c < 0:
+			return false // the x doesn't exist in the other
+		case c > 0:
+			y = b.successor(y)
+		default:
+			x, y = a.successor(x), b.successor(y)
+		}
+	}
+	return true
+}
+
+// IsSuperset returns true, if all keys of the other exist in the Set
+// O(n+m).
+func (s *Set[Key]) IsSuperset(other *Set[Key]) bool {
+	return other.IsSubset(s)
+}
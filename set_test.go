@@ -0,0 +1,118 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"slices"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet(t *testing.T) {
+
+	var s = NewSet(3, 1, 2)
+
+	assert.Equal(t, 3, s.Len())
+	assert.True(t, s.Add(4))
+	assert.False(t, s.Add(4))
+	assert.True(t, s.Contains(4))
+	assert.True(t, s.Remove(4))
+	assert.False(t, s.Remove(4))
+	assert.False(t, s.Contains(4))
+
+	assert.Equal(t, 1, s.Min())
+	assert.Equal(t, 3, s.Max())
+	assert.Equal(t, []int{1, 2, 3}, slices.Collect(s.All()))
+	assert.Equal(t, []int{3, 2}, slices.Collect(s.Range(5, 2)))
+	assert.Equal(t, []int{2, 3}, s.Keys(2, 10))
+
+	var walked []int
+	var err = s.Walk(0, 10, func(key int) error {
+		walked = append(walked, key)
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 2, 3}, walked)
+
+	var c = s.Clone()
+	c.Add(10)
+	assert.False(t, s.Contains(10))
+
+	s.Empty()
+	assert.Equal(t, 0, s.Len())
+	assert.Zero(t, s.Min())
+}
+
+func TestSet_algebra(t *testing.T) {
+
+	var a, b = NewSet(1, 2, 3), NewSet(2, 3, 4)
+
+	assert.Equal(t, []int{1, 2, 3, 4}, slices.Collect(a.Union(b).All()))
+	assert.Equal(t, []int{2, 3}, slices.Collect(a.Intersect(b).All()))
+	assert.Equal(t, []int{1}, slices.Collect(a.Difference(b).All()))
+	assert.Equal(t, []int{1, 4},
+		slices.Collect(a.SymmetricDifference(b).All()))
+
+	assert.False(t, a.Equal(b))
+	assert.True(t, a.Equal(NewSet(3, 2, 1)))
+	assert.True(t, NewSet[int]().Equal(NewSet[int]()))
+	assert.False(t, a.Equal(NewSet(1, 2)))
+
+	assert.True(t, NewSet(2, 3).IsSubset(a))
+	assert.True(t, NewSet[int]().IsSubset(a))
+	assert.True(t, a.IsSubset(a))
+	assert.False(t, NewSet(2, 5).IsSubset(a))
+	assert.False(t, NewSet(0, 1).IsSubset(a))
+	assert.False(t, a.IsSubset(NewSet(1, 2)))
+	assert.True(t, a.IsSuperset(NewSet(1, 3)))
+	assert.False(t, a.IsSuperset(b))
+}
+
+func TestSet_noValueMemory(t *testing.T) {
+
+	type nodeWithoutValue struct {
+		left, right, parent *nodeWithoutValue
+		size                int
+		gen                 uint64
+		color               color
+		key                 int
+	}
+
+	assert.Equal(t, unsafe.Sizeof(nodeWithoutValue{}),
+		unsafe.Sizeof(node[int, struct{}]{}))
+}
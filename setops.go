@@ -0,0 +1,135 @@
+package rbtree
+
+// ResolveFunc returns value for a key that exists in both trees, the a is
+// value of the first tree, and the b is value of the second one.
+type ResolveFunc[Key, Value any] func(key Key, a, b Value) Value
+
+// what elements a merge keeps
+const (
+	keepA    = 1 << iota // keys only in the first tree
+	keepB                // keys only in the second tree
+	keepBoth             // keys in both trees
+)
+
+// merge walks both trees in order and returns kept elements in ascending
+// order. It takes O(n+m).
+func merge[Key, Value any](a, b *Tree[Key, Value], keep int,
+	resolve ResolveFunc[Key, Value]) (keys []Key, values []Value) {
+
+	var (
+		x, y = a.minimum(a.root), b.minimum(b.root)
+
+		add = func(mask int, key Key, value Value) {
+			if keep&mask != 0 {
+				keys, values = append(keys, key), append(values, value)
+			}
+		}
+	)
+
+	for x != a.sentinel && y != b.sentinel {
+		switch c := a.cmp(x.key, y.key); {
+		case c < 0:
+			add(keepA, x.key, x.value)
+			x = a.successor(x)
+		case c > 0:
+			add(keepB, y.key, y.value)
+			y = b.successor(y)
+		default:
+			if keep&keepBoth != 0 {
+				var value = y.value
+				if resolve != nil {
+					value = resolve(x.key, x.value, y.value)
+				}
+				add(keepBoth, x.key, value)
+			}
+			x, y = a.successor(x), b.successor(y)
+		}
+	}
+
+	for ; x != a.sentinel; x = a.successor(x) {
+		add(keepA, x.key, x.value)
+	}
+	for ; y != b.sentinel; y = b.successor(y) {
+		add(keepB, y.key, y.value)
+	}
+
+	return
+}
+
+// combine returns new tree with merged elements, it uses comparison
+// function of the a
+func combine[Key, Value any](a, b *Tree[Key, Value], keep int,
+	resolve ResolveFunc[Key, Value]) (tr *Tree[Key, Value]) {
+
+	tr = a.newTree()
+	tr.mergeFrom(a, b, keep, resolve)
+	return
+}
+
+// mergeFrom replaces content of the Tree with merged elements
+func (t *Tree[Key, Value]) mergeFrom(a, b *Tree[Key, Value], keep int,
+	resolve ResolveFunc[Key, Value]) {
+
+	var keys, values = merge(a, b, keep, resolve)
+	t.buildSlices(keys, values) // sorted, can't fail
+}
+
+// Union returns new tree with keys of both trees O(n+m). For a key that
+// exists in both trees the resolve function is used to choose value. If
+// the resolve is nil, then value of the b is used. Both trees must use
+// the same order of keys.
+func Union[Key, Value any](a, b *Tree[Key, Value],
+	resolve ResolveFunc[Key, Value]) *Tree[Key, Value] {
+
+	return combine(a, b, keepA|keepB|keepBoth, resolve)
+}
+
+// Intersect returns new tree with keys that exist in both trees O(n+m).
+// The resolve function is used to choose value. If the resolve is nil,
+// then value of the b is used.
+func Intersect[Key, Value any](a, b *Tree[Key, Value],
+	resolve ResolveFunc[Key, Value]) *Tree[Key, Value] {
+
+	return combine(a, b, keepBoth, resolve)
+}
+
+// Difference returns new tree with keys of the a that don't exist
+// in the b O(n+m).
+func Difference[Key, Value any](a, b *Tree[Key, Value]) *Tree[Key, Value] {
+	return combine(a, b, keepA, nil)
+}
+
+// SymmetricDifference returns new tree with keys that exist only in one
+// of the trees O(n+m).
+func SymmetricDifference[Key, Value any](a,
+	b *Tree[Key, Value]) *Tree[Key, Value] {
+
+	return combine(a, b, keepA|keepB, nil)
+}
+
+// UnionWith adds all keys of the other tree to the Tree O(n+m).
+// See Union for details.
+func (t *Tree[Key, Value]) UnionWith(other *Tree[Key, Value],
+	resolve ResolveFunc[Key, Value]) {
+
+	t.mergeFrom(t, other, keepA|keepB|keepBoth, resolve)
+}
+
+// IntersectWith removes all keys of the Tree that don't exist in the other
+// tree O(n+m). See Intersect for details.
+func (t *Tree[Key, Value]) IntersectWith(other *Tree[Key, Value],
+	resolve ResolveFunc[Key, Value]) {
+
+	t.mergeFrom(t, other, keepBoth, resolve)
+}
+
+// DifferenceWith removes all keys of the other tree from the Tree O(n+m).
+func (t *Tree[Key, Value]) DifferenceWith(other *Tree[Key, Value]) {
+	t.mergeFrom(t, other, keepA, nil)
+}
+
+// SymmetricDifferenceWith replaces content of the Tree with keys that exist
+// only in one of the trees O(n+m).
+func (t *Tree[Key, Value]) SymmetricDifferenceWith(other *Tree[Key, Value]) {
+	t.mergeFrom(t, other, keepA|keepB, nil)
+}
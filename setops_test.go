@@ -0,0 +1,172 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnion(t *testing.T) {
+
+	var a, b = New[int, string](), New[int, string]()
+	a.Set(1, "a1")
+	a.Set(2, "a2")
+	b.Set(2, "b2")
+	b.Set(3, "b3")
+
+	var tr = Union(a, b, nil)
+	assert.Equal(t, []string{"a1", "b2", "b3"}, tr.Slice(0, 10))
+	checkTree(t, tr)
+
+	tr = Union(a, b, func(key int, x, y string) string { return x + y })
+	assert.Equal(t, []string{"a1", "a2b2", "b3"}, tr.Slice(0, 10))
+
+	tr = Intersect(a, b, nil)
+	assert.Equal(t, []string{"b2"}, tr.Slice(0, 10))
+	tr = Intersect(a, b, func(key int, x, y string) string { return x })
+	assert.Equal(t, []string{"a2"}, tr.Slice(0, 10))
+
+	tr = Difference(a, b)
+	assert.Equal(t, []string{"a1"}, tr.Slice(0, 10))
+	tr = Difference(b, a)
+	assert.Equal(t, []string{"b3"}, tr.Slice(0, 10))
+
+	tr = SymmetricDifference(a, b)
+	assert.Equal(t, []string{"a1", "b3"}, tr.Slice(0, 10))
+
+	// source trees are not changed
+	assert.Equal(t, []string{"a1", "a2"}, a.Slice(0, 10))
+	assert.Equal(t, []string{"b2", "b3"}, b.Slice(0, 10))
+
+	// empty
+	var e = New[int, string]()
+	assert.Equal(t, 2, Union(a, e, nil).Len())
+	assert.Equal(t, 2, Union(e, a, nil).Len())
+	assert.Zero(t, Intersect(a, e, nil).Len())
+	assert.Equal(t, 2, Difference(a, e).Len())
+	assert.Zero(t, Difference(e, a).Len())
+}
+
+func TestTree_UnionWith(t *testing.T) {
+
+	var newTrees = func() (a, b *Tree[int, int]) {
+		a, b = New[int, int](), New[int, int]()
+		for _, k := range []int{1, 2, 3, 4} {
+			a.Set(k, k)
+		}
+		for _, k := range []int{3, 4, 5} {
+			b.Set(k, -k)
+		}
+		return
+	}
+
+	var a, b = newTrees()
+	a.UnionWith(b, func(_ int, x, y int) int { return x * 10 })
+	assert.Equal(t, []int{1, 2, 30, 40, -5}, a.Slice(0, 10))
+	checkTree(t, a)
+
+	a, b = newTrees()
+	a.IntersectWith(b, nil)
+	assert.Equal(t, []int{-3, -4}, a.Slice(0, 10))
+	checkTree(t, a)
+
+	a, b = newTrees()
+	a.DifferenceWith(b)
+	assert.Equal(t, []int{1, 2}, a.Slice(0, 10))
+	checkTree(t, a)
+
+	a, b = newTrees()
+	a.SymmetricDifferenceWith(b)
+	assert.Equal(t, []int{1, 2, -5}, a.Slice(0, 10))
+	checkTree(t, a)
+
+	// with itself
+	a, _ = newTrees()
+	a.UnionWith(a, nil)
+	assert.Equal(t, []int{1, 2, 3, 4}, a.Slice(0, 10))
+	a.DifferenceWith(a)
+	assert.Zero(t, a.Len())
+}
+
+func Test_randomSetOperations(t *testing.T) {
+
+	var (
+		a, b   = New[int, int](), New[int, int]()
+		ma, mb = make(map[int]bool), make(map[int]bool)
+	)
+	for i := 0; i < Count; i++ {
+		var k = rand.Intn(Count)
+		a.Set(k, k)
+		ma[k] = true
+		k = rand.Intn(Count)
+		b.Set(k, k)
+		mb[k] = true
+	}
+
+	var expected = func(pred func(inA, inB bool) bool) (keys []int) {
+		for k := 0; k < Count; k++ {
+			if pred(ma[k], mb[k]) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Ints(keys)
+		return
+	}
+
+	for _, tt := range []struct {
+		name string
+		tr   *Tree[int, int]
+		pred func(inA, inB bool) bool
+	}{
+		{"union", Union(a, b, nil),
+			func(inA, inB bool) bool { return inA || inB }},
+		{"intersect", Intersect(a, b, nil),
+			func(inA, inB bool) bool { return inA && inB }},
+		{"difference", Difference(a, b),
+			func(inA, inB bool) bool { return inA && !inB }},
+		{"symmetric difference", SymmetricDifference(a, b),
+			func(inA, inB bool) bool { return inA != inB }},
+	} {
+		assert.Equal(t, expected(tt.pred),
+			tt.tr.SliceKeys(math.MinInt, math.MaxInt), tt.name)
+		checkTree(t, tt.tr)
+	}
+}
@@ -0,0 +1,336 @@
+package rbtree
+
+import (
+	"cmp"
+	"slices"
+	"sort"
+	"sync"
+
+	"golang.org/x/exp/constraints"
+)
+
+// shard of a ShardedTree
+type shard[Key, Value any] struct {
+	mx   sync.RWMutex
+	tree *Tree[Key, Value]
+}
+
+// ShardedTree is a thread-safe tree, that partitions keys by ranges to
+// shards. Every shard is a Tree with its own lock, thus writers of
+// different shards don't block each other. The ShardedTree implements
+// TreeInterface.
+//
+// Operations on many shards, such as Walk, Slice or Len, lock the shards
+// one by one, thus they are not atomic against concurrent writers.
+type ShardedTree[Key, Value any] struct {
+	mx     sync.RWMutex // bounds, locked for reading by all operations
+	cmp    CompareFunc[Key]
+	bounds []Key // the i-th shard keeps keys in [bounds[i-1], bounds[i])
+	shards []*shard[Key, Value]
+}
+
+// NewSharded creates the new empty ShardedTree with shards divided by given
+// boundaries, n boundaries create n+1 shards. See also Rebalance.
+func NewSharded[Key constraints.Ordered,
+	Value any](bounds ...Key) (st *ShardedTree[Key, Value]) {
+
+	st = NewShardedFunc[Key, Value](cmp.Compare[Key], bounds...)
+	var o = newOrdered[Key, Value]()
+	for _, s := range st.shards {
+		s.tree.ordered = o
+	}
+	return
+}
+
+// NewShardedFunc is like the NewSharded, but the tree uses given function
+// to compare keys. See also NewFunc.
+func NewShardedFunc[Key, Value any](cmp CompareFunc[Key],
+	bounds ...Key) (st *ShardedTree[Key, Value]) {
+
+	st = &ShardedTree[Key, Value]{
+		cmp:    cmp,
+		bounds: slices.Clone(bounds),
+		shards: make([]*shard[Key, Value], len(bounds)+1),
+	}
+	slices.SortFunc(st.bounds, cmp)
+	for i := range st.shards {
+		st.shards[i] = &shard[Key, Value]{tree: NewFunc[Key, Value](cmp)}
+	}
+	return
+}
+
+// index of shard of given key
+func (t *ShardedTree[Key, Value]) index(key Key) int {
+	return sort.Search(len(t.bounds), func(i int) bool {
+		return t.cmp(t.bounds[i], key) > 0
+	})
+}
+
+// read calls given function for shard of the key under read locks
+func (t *ShardedTree[Key, Value]) read(key Key, fn func(tr *Tree[Key, Value])) {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	var s = t.shards[t.index(key)]
+	s.mx.RLock()
+	defer s.mx.RUnlock()
+
+	fn(s.tree)
+}
+
+// write calls given function for shard of the key under write lock
+func (t *ShardedTree[Key, Value]) write(key Key, fn func(tr *Tree[Key, Value])) {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	var s = t.shards[t.index(key)]
+	s.mx.Lock()
+	defer s.mx.Unlock()
+
+	fn(s.tree)
+}
+
+// Set the value. O(logn). This will overwrite the existing value.
+func (t *ShardedTree[Key, Value]) Set(key Key, value Value) (added bool) {
+	t.write(key, func(tr *Tree[Key, Value]) { added = tr.Set(key, value) })
+	return
+}
+
+// SetNx doesn't overwrites an existing value.
+func (t *ShardedTree[Key, Value]) SetNx(key Key, value Value) (added bool) {
+	t.write(key, func(tr *Tree[Key, Value]) { added = tr.SetNx(key, value) })
+	return
+}
+
+// Del deletes value by key. O(logn). It returns false,
+// if key doesn't exits.
+func (t *ShardedTree[Key, Value]) Del(key Key) (deleted bool) {
+	t.write(key, func(tr *Tree[Key, Value]) { deleted = tr.Del(key) })
+	return
+}
+
+// Get O(logn). It returns zero value, if key doesn't exist.
+func (t *ShardedTree[Key, Value]) Get(key Key) (value Value) {
+	t.read(key, func(tr *Tree[Key, Value]) { value = tr.Get(key) })
+	return
+}
+
+// GetEx O(logn). It returns false, if key doesn't exist.
+func (t *ShardedTree[Key, Value]) GetEx(key Key) (value Value, ok bool) {
+	t.read(key, func(tr *Tree[Key, Value]) { value, ok = tr.GetEx(key) })
+	return
+}
+
+// IsExist O(logn)
+func (t *ShardedTree[Key, Value]) IsExist(key Key) (ok bool) {
+	t.read(key, func(tr *Tree[Key, Value]) { ok = tr.IsExist(key) })
+	return
+}
+
+// neighbour looks for a key using given method of shards starting from
+// shard of the key in given direction
+func (t *ShardedTree[Key, Value]) neighbour(key Key, step int,
+	method func(tr *Tree[Key, Value], key Key) (Key, Value, bool)) (
+	k Key, v Value, ok bool) {
+
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	for i := t.index(key); i >= 0 && i < len(t.shards) && !ok; i += step {
+		var s = t.shards[i]
+		s.mx.RLock()
+		k, v, ok = method(s.tree, key)
+		s.mx.RUnlock()
+	}
+	return
+}
+
+// Floor returns the greatest key less than or equal to the given one and
+// its value O(logn). It returns false, if there is no such key.
+func (t *ShardedTree[Key, Value]) Floor(key Key) (Key, Value, bool) {
+	return t.neighbour(key, -1, (*Tree[Key, Value]).Floor)
+}
+
+// Ceiling returns the smallest key greater than or equal to the given one
+// and its value O(logn). It returns false, if there is no such key.
+func (t *ShardedTree[Key, Value]) Ceiling(key Key) (Key, Value, bool) {
+	return t.neighbour(key, 1, (*Tree[Key, Value]).Ceiling)
+}
+
+// Lower returns the greatest key strictly less than the given one and
+// its value O(logn). It returns false, if there is no such key.
+func (t *ShardedTree[Key, Value]) Lower(key Key) (Key, Value, bool) {
+	return t.neighbour(key, -1, (*Tree[Key, Value]).Lower)
+}
+
+// Higher returns the smallest key strictly greater than the given one and
+// its value O(logn). It returns false, if there is no such key.
+func (t *ShardedTree[Key, Value]) Higher(key Key) (Key, Value, bool) {
+	return t.neighbour(key, 1, (*Tree[Key, Value]).Higher)
+}
+
+// Len O(number of shards)
+func (t *ShardedTree[Key, Value]) Len() (n int) {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	for _, s := range t.shards {
+		s.mx.RLock()
+		n += s.tree.Len()
+		s.mx.RUnlock()
+	}
+	return
+}
+
+// Empty makes the tree empty O(number of shards).
+func (t *ShardedTree[Key, Value]) Empty() {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	for _, s := range t.shards {
+		s.tree.Empty()
+	}
+}
+
+// Move moves the value from one index to another. Silent.
+// It just changes index of value O(2logn). It locks both shards.
+func (t *ShardedTree[Key, Value]) Move(oldKey, newKey Key) (moved bool) {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	var (
+		i, j     = t.index(oldKey), t.index(newKey)
+		src, dst = t.shards[i], t.shards[j]
+	)
+
+	// lock in order of shards to avoid deadlocks
+	t.shards[min(i, j)].mx.Lock()
+	defer t.shards[min(i, j)].mx.Unlock()
+	if i == j {
+		return src.tree.Move(oldKey, newKey)
+	}
+	t.shards[max(i, j)].mx.Lock()
+	defer t.shards[max(i, j)].mx.Unlock()
+
+	var value, ok = src.tree.GetEx(oldKey)
+	if !ok {
+		return
+	}
+	src.tree.Del(oldKey)
+	dst.tree.Set(newKey, value)
+	return true
+}
+
+// Max returns maximum index and its value O(number of shards)
+func (t *ShardedTree[Key, Value]) Max() (key Key, value Value) {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	for i := len(t.shards) - 1; i >= 0; i-- {
+		var s = t.shards[i]
+		s.mx.RLock()
+		var ok = s.tree.Len() > 0
+		if ok {
+			key, value = s.tree.Max()
+		}
+		s.mx.RUnlock()
+		if ok {
+			break
+		}
+	}
+	return
+}
+
+// Min returns minimum indexed and its value O(number of shards)
+func (t *ShardedTree[Key, Value]) Min() (key Key, value Value) {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	for _, s := range t.shards {
+		s.mx.RLock()
+		var ok = s.tree.Len() > 0
+		if ok {
+			key, value = s.tree.Min()
+		}
+		s.mx.RUnlock()
+		if ok {
+			break
+		}
+	}
+	return
+}
+
+// Walk on the tree. See Tree.Walk for details. Shards are walked one by
+// one in order of keys, and only walked shard is locked for reading. The
+// tree can't be modified inside the WalkFunc.
+func (t *ShardedTree[Key, Value]) Walk(from, to Key,
+	walkFunc WalkFunc[Key, Value]) (err error) {
+
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	var first, last, step = t.index(from), t.index(to), 1
+	if first > last {
+		step = -1
+	}
+	for i := first; ; i += step {
+		var s = t.shards[i]
+		s.mx.RLock()
+		err = s.tree.Walk(from, to, walkFunc)
+		s.mx.RUnlock()
+		if err != nil || i == last {
+			return
+		}
+	}
+}
+
+// Slice returns all values at given range if any.
+func (t *ShardedTree[Key, Value]) Slice(from, to Key) (vals []Value) {
+	t.Walk(from, to, func(_ Key, value Value) error {
+		vals = append(vals, value)
+		return nil
+	})
+	return
+}
+
+// SliceKeys returns all keys at given range if any.
+func (t *ShardedTree[Key, Value]) SliceKeys(from, to Key) (keys []Key) {
+	t.Walk(from, to, func(key Key, _ Value) error {
+		keys = append(keys, key)
+		return nil
+	})
+	return
+}
+
+// Bounds returns current boundaries of shards.
+func (t *ShardedTree[Key, Value]) Bounds() []Key {
+	t.mx.RLock()
+	defer t.mx.RUnlock()
+
+	return slices.Clone(t.bounds)
+}
+
+// Rebalance moves boundaries of shards to make the shards equal in size.
+// It blocks all other operations, and it takes O(s*logn), where s is
+// number of shards. The boundaries are not changed for an empty tree.
+func (t *ShardedTree[Key, Value]) Rebalance() {
+	t.mx.Lock()
+	defer t.mx.Unlock()
+
+	var all = t.shards[0].tree
+	for _, s := range t.shards[1:] {
+		all, _ = Join(all, s.tree) // sorted by the shards
+	}
+
+	var n = all.Len()
+	if n > 0 {
+		for i := range t.bounds {
+			t.bounds[i], _, _ = all.Select((i + 1) * n / len(t.shards))
+		}
+	}
+
+	for i := len(t.bounds); i > 0; i-- {
+		all, t.shards[i].tree = all.Split(t.bounds[i-1])
+	}
+	t.shards[0].tree = all
+}
@@ -0,0 +1,193 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var _ TreeInterface[int, int] = (*ShardedTree[int, int])(nil)
+
+func TestShardedTree(t *testing.T) {
+
+	var (
+		rnd = rand.New(rand.NewSource(42))
+		st  = NewSharded[int, int](300, 100, 200)
+		tr  = New[int, int]()
+	)
+
+	assert.Equal(t, []int{100, 200, 300}, st.Bounds())
+	var key, value = st.Min()
+	assert.Zero(t, key)
+	assert.Zero(t, value)
+
+	for i := 0; i < 5000; i++ {
+		var key, other = rnd.Intn(400), rnd.Intn(400)
+		switch rnd.Intn(4) {
+		case 0:
+			assert.Equal(t, tr.Set(key, i), st.Set(key, i))
+		case 1:
+			assert.Equal(t, tr.SetNx(key, i), st.SetNx(key, i))
+		case 2:
+			assert.Equal(t, tr.Del(key), st.Del(key))
+		case 3:
+			assert.Equal(t, tr.Move(key, other), st.Move(key, other))
+		}
+	}
+
+	for _, key := range []int{0, 100, 150, 399, 400} {
+		assert.Equal(t, tr.Move(key, key), st.Move(key, key))
+	}
+
+	assert.Equal(t, tr.Len(), st.Len())
+	assert.Equal(t, tr.Slice(-1, 500), st.Slice(-1, 500))
+	assert.Equal(t, tr.SliceKeys(350, 50), st.SliceKeys(350, 50))
+	assert.Equal(t, tr.SliceKeys(150, 150), st.SliceKeys(150, 150))
+	for key := -1; key <= 401; key++ {
+		var value, ok = st.GetEx(key)
+		var want, wantOk = tr.GetEx(key)
+		assert.Equal(t, wantOk, ok)
+		assert.Equal(t, want, value)
+		assert.Equal(t, tr.IsExist(key), st.IsExist(key))
+		assert.Equal(t, tr.Get(key), st.Get(key))
+
+		var k, v, ok1 = st.Floor(key)
+		var wk, wv, wok = tr.Floor(key)
+		assert.Equal(t, []any{wk, wv, wok}, []any{k, v, ok1})
+		k, v, ok1 = st.Ceiling(key)
+		wk, wv, wok = tr.Ceiling(key)
+		assert.Equal(t, []any{wk, wv, wok}, []any{k, v, ok1})
+		k, v, ok1 = st.Lower(key)
+		wk, wv, wok = tr.Lower(key)
+		assert.Equal(t, []any{wk, wv, wok}, []any{k, v, ok1})
+		k, v, ok1 = st.Higher(key)
+		wk, wv, wok = tr.Higher(key)
+		assert.Equal(t, []any{wk, wv, wok}, []any{k, v, ok1})
+	}
+
+	var wantKey, wantValue = tr.Min()
+	key, value = st.Min()
+	assert.Equal(t, wantKey, key)
+	assert.Equal(t, wantValue, value)
+	wantKey, wantValue = tr.Max()
+	key, value = st.Max()
+	assert.Equal(t, wantKey, key)
+	assert.Equal(t, wantValue, value)
+
+	var err = st.Walk(0, 400, func(int, int) error { return ErrStop })
+	assert.ErrorIs(t, err, ErrStop)
+
+	st.Empty()
+	assert.Equal(t, 0, st.Len())
+}
+
+func TestShardedTree_Rebalance(t *testing.T) {
+
+	var st = NewSharded[int, int](10, 20, 30)
+
+	st.Rebalance() // empty
+	assert.Equal(t, []int{10, 20, 30}, st.Bounds())
+
+	for i := 100; i < 500; i++ {
+		st.Set(i, i) // all in the last shard
+	}
+	assert.Equal(t, 400, st.shards[3].tree.Len())
+
+	st.Rebalance()
+	assert.Equal(t, []int{200, 300, 400}, st.Bounds())
+	for _, s := range st.shards {
+		assert.Equal(t, 100, s.tree.Len())
+		checkTree(t, s.tree)
+	}
+	assert.Equal(t, 400, st.Len())
+	assert.Equal(t, 499, st.Get(499))
+
+	// less keys than shards
+	st.Empty()
+	st.Set(1, 1)
+	st.Set(2, 2)
+	st.Rebalance()
+	assert.Equal(t, []int{1, 2, 2}, st.Bounds())
+	assert.Equal(t, []int{1, 2}, st.SliceKeys(0, 10))
+	assert.Equal(t, []int{2, 1}, st.SliceKeys(10, 0))
+}
+
+func TestShardedTree_race(t *testing.T) {
+
+	var (
+		st = NewSharded[int, int](250, 500, 750)
+		wg sync.WaitGroup
+	)
+
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var rnd = rand.New(rand.NewSource(int64(i)))
+			for j := 0; j < 2000; j++ {
+				var key = rnd.Intn(1000)
+				switch rnd.Intn(5) {
+				case 0:
+					st.Del(key)
+				case 1:
+					st.Move(key, rnd.Intn(1000))
+				case 2:
+					st.Walk(key, key+100, func(int, int) error { return nil })
+				default:
+					st.Set(key, j)
+				}
+			}
+		}(i)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for j := 0; j < 10; j++ {
+			st.Rebalance()
+		}
+	}()
+	wg.Wait()
+
+	var keys = st.SliceKeys(0, 1000)
+	assert.Equal(t, st.Len(), len(keys))
+	for i := 1; i < len(keys); i++ {
+		assert.Less(t, keys[i-1], keys[i])
+	}
+}
@@ -0,0 +1,211 @@
+package rbtree
+
+// blackHeight returns number of black nodes on a path from the x
+// to a leaf, the x included, the sentinel is not.
+func (t *Tree[Key, Value]) blackHeight(x *node[Key, Value]) (h int) {
+	for ; x != t.sentinel; x = x.left {
+		if x.color == black {
+			h++
+		}
+	}
+	return
+}
+
+// unlink makes the owned x root of a separate tree, the x can be the
+// sentinel. It returns black height of the new tree, where h is black
+// height of the x in its previous tree.
+func (t *Tree[Key, Value]) unlink(x *node[Key, Value], h int) int {
+	if x == t.sentinel {
+		return h
+	}
+	x.parent = nil
+	if x.color == red {
+		x.color = black
+		h++
+	}
+	return h
+}
+
+// join returns root of a tree with all nodes of the l, the x and the r,
+// and black height of the tree. The l and the r are roots with black
+// color, the x is owned by the Tree. All keys of the l are less than key
+// of the x, and all keys of the r are greater. The lh and the rh are black
+// heights of the l and the r. It takes O(|lh-rh|+1).
+func (t *Tree[Key, Value]) join(l, x, r *node[Key, Value], lh, rh int) (
+	root *node[Key, Value], h int) {
+
+	if lh == rh {
+		x.left, x.right, x.parent = l, r, nil
+		x.color, x.size = black, l.size+r.size+1
+		t.setParent(l, x)
+		t.setParent(r, x)
+		t.augmentPath(x)
+		return x, lh + 1
+	}
+
+	var (
+		scratch *Tree[Key, Value]
+
+		c, p *node[Key, Value] // c is a black node with proper height
+		ch   int               // black height of the c
+		grow int               // size of the attached tree
+	)
+
+	if lh > rh {
+		scratch, ch, grow = t.scratch(l), lh, r.size+1
+		c = scratch.ownRoot()
+		for c.color == red || ch != rh {
+			if c.color == black {
+				ch--
+			}
+			p, c = c, scratch.ownRight(c)
+		}
+		x.left, x.right, p.right = c, r, x
+	} else {
+		scratch, ch, grow = t.scratch(r), rh, l.size+1
+		c = scratch.ownRoot()
+		for c.color == red || ch != lh {
+			if c.color == black {
+				ch--
+			}
+			p, c = c, scratch.ownLeft(c)
+		}
+		x.left, x.right, p.left = l, c, x
+	}
+
+	x.parent, x.color = p, red
+	x.size = x.left.size + x.right.size + 1
+	t.setParent(x.left, x)
+	t.setParent(x.right, x)
+	for ; p != nil; p = p.parent {
+		p.size += grow
+	}
+	t.augmentPath(x)
+
+	h = max(lh, rh)
+	if scratch.insertFixup(x) {
+		h++
+	}
+	t.stale = t.stale || scratch.stale
+	return scratch.root, h
+}
+
+// concat returns root of a tree with all nodes of the l and the r, where
+// all keys of the l are less than keys of the r. The l and the r are black
+// roots, the lh is black height of the l. It takes O(logn).
+func (t *Tree[Key, Value]) concat(l, r *node[Key, Value],
+	lh int) *node[Key, Value] {
+
+	switch {
+	case r == t.sentinel:
+		return l
+	case l == t.sentinel:
+		return r
+	}
+
+	// remove minimum of the r and use it to join
+	var (
+		scratch = t.scratch(r)
+		x       = scratch.own(scratch.minimum(r))
+	)
+	scratch.deleteNode(x) // the x has no left child, thus it's removed
+	t.stale = t.stale || scratch.stale
+	var root, _ = t.join(l, x, scratch.root, lh,
+		scratch.blackHeight(scratch.root))
+	return root
+}
+
+// scratch returns a temporary Tree to change subtree of the root, it owns
+// the same nodes as the Tree
+func (t *Tree[Key, Value]) scratch(root *node[Key, Value]) (
+	s *Tree[Key, Value]) {
+
+	s = &Tree[Key, Value]{sentinel: t.sentinel, root: root, len: root.size,
+		cmp: t.cmp, ordered: t.ordered, augment: t.augment}
+	s.gen.Store(t.gen.Load())
+	return
+}
+
+// split splits subtree of the owned x with black height h by given key. All
+// keys of the l are less than the key, and all keys of the r are greater
+// or equal. If the inclusive is true, then the key itself goes to the l.
+// The lh and the rh are black heights of the l and the r.
+func (t *Tree[Key, Value]) split(x *node[Key, Value], h int, key Key,
+	inclusive bool) (l *node[Key, Value], lh int, r *node[Key, Value],
+	rh int) {
+
+	if x == t.sentinel {
+		return t.sentinel, 0, t.sentinel, 0
+	}
+
+	var (
+		left, right = t.ownLeft(x), t.ownRight(x)
+		leftHeight  = t.unlink(left, h-1)
+		rightHeight = t.unlink(right, h-1)
+	)
+
+	if c := t.cmp(key, x.key); c < 0 || c == 0 && !inclusive {
+		l, lh, r, rh = t.split(left, leftHeight, key, inclusive)
+		r, rh = t.join(r, x, right, rh, rightHeight)
+		return
+	}
+
+	l, lh, r, rh = t.split(right, rightHeight, key, inclusive)
+	l, lh = t.join(left, x, l, leftHeight, lh)
+	return
+}
+
+// Split moves all keys less than given one to the left tree, and all
+// other keys to the right tree O(logn). The Tree becomes empty. Both
+// trees use comparison function of the Tree.
+func (t *Tree[Key, Value]) Split(key Key) (left, right *Tree[Key, Value]) {
+
+	t.recordSnapshot()
+	var l, _, r, _ = t.split(t.ownRoot(), t.blackHeight(t.root), key, false)
+
+	left, right = t.newTree(), t.newTree()
+	left.reset(l, l.size)
+	right.reset(r, r.size)
+	left.stale, right.stale = t.stale, t.stale
+	t.clear()
+	return
+}
+
+// Join moves all keys of the left and the right trees to new tree
+// O(logn). All keys of the left must be less than keys of the right,
+// otherwise ErrNotSorted returned and the trees are not changed. The new
+// tree uses comparison function of the left. Both trees become empty.
+func Join[Key, Value any](left, right *Tree[Key, Value]) (
+	tr *Tree[Key, Value], err error) {
+
+	// the new tree owns nodes of the left, or of the right if the left is
+	// empty, thus it takes generation after the snapshots changed it
+	switch {
+	case right.len == 0:
+		left.recordSnapshot()
+		tr = left.newTree()
+		tr.reset(left.root, left.len)
+		tr.stale = left.stale
+	case left.len == 0:
+		right.recordSnapshot()
+		tr = left.newTree()
+		tr.gen.Store(right.gen.Load())
+		tr.reset(right.root, right.len)
+		tr.stale = right.stale
+	default:
+		var x = right.minimum(right.root)
+		if left.cmp(left.maximum(left.root).key, x.key) >= 0 {
+			return nil, ErrNotSorted
+		}
+		left.recordSnapshot()
+		right.recordSnapshot()
+		tr = left.newTree()
+		tr.stale = left.stale || right.stale
+		tr.reset(tr.concat(left.root, right.root,
+			left.blackHeight(left.root)), left.len+right.len)
+	}
+
+	left.clear()
+	right.clear()
+	return
+}
@@ -0,0 +1,210 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree_Split(t *testing.T) {
+
+	var tr = New[int, int]()
+	for i := 0; i < 10; i++ {
+		tr.Set(i*2, i*2)
+	}
+
+	var left, right = tr.Split(7)
+	assert.Equal(t, []int{0, 2, 4, 6}, slices.Collect(left.Keys()))
+	assert.Equal(t, []int{8, 10, 12, 14, 16, 18}, slices.Collect(right.Keys()))
+	assert.Equal(t, 4, left.Len())
+	assert.Equal(t, 6, right.Len())
+	assert.Equal(t, 0, tr.Len())
+	checkTree(t, left)
+	checkTree(t, right)
+	checkTree(t, tr)
+
+	// the key goes to the right
+	left, right = right.Split(12)
+	assert.Equal(t, []int{8, 10}, slices.Collect(left.Keys()))
+	assert.Equal(t, []int{12, 14, 16, 18}, slices.Collect(right.Keys()))
+
+	// bounds
+	left, right = right.Split(-1)
+	assert.Equal(t, 0, left.Len())
+	assert.Equal(t, 4, right.Len())
+	left, right = right.Split(100)
+	assert.Equal(t, 4, left.Len())
+	assert.Equal(t, 0, right.Len())
+	checkTree(t, left)
+	checkTree(t, right)
+
+	// empty
+	left, right = New[int, int]().Split(0)
+	assert.Equal(t, 0, left.Len())
+	assert.Equal(t, 0, right.Len())
+}
+
+func TestJoin(t *testing.T) {
+
+	var left, right = New[int, int](), New[int, int]()
+	for i := 0; i < 5; i++ {
+		left.Set(i, i)
+	}
+	for i := 5; i < 100; i++ {
+		right.Set(i, i)
+	}
+
+	var tr, err = Join(left, right)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, tr.Len())
+	assert.Equal(t, 0, left.Len())
+	assert.Equal(t, 0, right.Len())
+	checkTree(t, tr)
+	for i := 0; i < 100; i++ {
+		var value, ok = tr.GetEx(i)
+		assert.True(t, ok)
+		assert.Equal(t, i, value)
+	}
+
+	// empty
+	tr, err = Join(tr, New[int, int]())
+	assert.NoError(t, err)
+	assert.Equal(t, 100, tr.Len())
+	tr, err = Join(New[int, int](), tr)
+	assert.NoError(t, err)
+	assert.Equal(t, 100, tr.Len())
+	checkTree(t, tr)
+
+	// not sorted
+	left, right = New[int, int](), New[int, int]()
+	left.Set(5, 5)
+	right.Set(5, 5)
+	right.Set(6, 6)
+	tr, err = Join(left, right)
+	assert.ErrorIs(t, err, ErrNotSorted)
+	assert.Nil(t, tr)
+	assert.Equal(t, 1, left.Len())
+	assert.Equal(t, 2, right.Len())
+}
+
+func Test_randomSplitJoin(t *testing.T) {
+
+	var (
+		rnd = rand.New(rand.NewSource(42))
+		tr  = New[int, int]()
+	)
+
+	for i := 0; i < 1000; i++ {
+
+		if tr.Len() < 500 {
+			for j := rnd.Intn(100); j >= 0; j-- {
+				var key = rnd.Intn(2000)
+				tr.Set(key, key)
+			}
+		}
+
+		var (
+			n           = tr.Len()
+			key         = rnd.Intn(2200) - 100
+			want        = tr.Rank(key)
+			left, right = tr.Split(key)
+		)
+
+		checkTree(t, left)
+		checkTree(t, right)
+		if !assert.Equal(t, want, left.Len()) ||
+			!assert.Equal(t, n-want, right.Len()) {
+			return
+		}
+		if left.Len() > 0 {
+			var max, _ = left.Max()
+			assert.Less(t, max, key)
+		}
+		if right.Len() > 0 {
+			var min, _ = right.Min()
+			assert.GreaterOrEqual(t, min, key)
+		}
+
+		// drop some keys to make the trees of different height
+		for j := rnd.Intn(n + 1); j > 0 && left.Len() > 0; j-- {
+			var min, _ = left.Min()
+			left.Del(min)
+		}
+
+		var err error
+		if tr, err = Join(left, right); !assert.NoError(t, err) {
+			return
+		}
+		checkTree(t, tr)
+	}
+}
+
+func TestTree_sharedSentinel(t *testing.T) {
+
+	// all trees of the same type share the sentinel, it never changed
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			var (
+				rnd = rand.New(rand.NewSource(seed))
+				tr  = New[int, int]()
+			)
+			for j := 0; j < 10000; j++ {
+				var key = rnd.Intn(100)
+				if rnd.Intn(2) == 0 {
+					tr.Set(key, key)
+				} else {
+					tr.Del(key)
+				}
+			}
+			var left, right = tr.Split(50)
+			_, _ = Join(left, right)
+		}(int64(i))
+	}
+	wg.Wait()
+
+	var a, b = New[int, int](), New[int, int]()
+	assert.True(t, a.sentinel == b.sentinel)
+	assert.Equal(t, black, a.sentinel.color)
+	assert.Equal(t, 0, a.sentinel.size)
+}
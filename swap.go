@@ -0,0 +1,139 @@
+package rbtree
+
+// EqualFunc reports whether two values are equal.
+type EqualFunc[Value any] func(a, b Value) bool
+
+// equal compares values by the == operator, it panics if the values are
+// not comparable
+func equal[Value any](a, b Value) bool {
+	return any(a) == any(b)
+}
+
+// CompareAndSwap swaps the old and new values for the key, if the value
+// stored in the Tree is equal to the old one O(logn). The old value must
+// be of a comparable type, otherwise it panics, like sync.Map does. Use
+// the CompareAndSwapFunc for other types.
+func (t *Tree[Key, Value]) CompareAndSwap(key Key,
+	old, new Value) (swapped bool) {
+
+	return t.CompareAndSwapFunc(key, old, new, equal[Value])
+}
+
+// CompareAndSwapFunc is like the CompareAndSwap, but it uses given
+// function to compare the values.
+func (t *Tree[Key, Value]) CompareAndSwapFunc(key Key, old, new Value,
+	eq EqualFunc[Value]) (swapped bool) {
+
+	var n = t.findNode(key)
+	if n == t.sentinel || !eq(n.value, old) {
+		return
+	}
+	t.setValue(n, new)
+	return true
+}
+
+// CompareAndDelete deletes the key, if its value is equal to the old one
+// O(logn). The old value must be of a comparable type, otherwise it
+// panics, like sync.Map does. Use the CompareAndDeleteFunc for other types.
+func (t *Tree[Key, Value]) CompareAndDelete(key Key,
+	old Value) (deleted bool) {
+
+	return t.CompareAndDeleteFunc(key, old, equal[Value])
+}
+
+// CompareAndDeleteFunc is like the CompareAndDelete, but it uses given
+// function to compare the values.
+func (t *Tree[Key, Value]) CompareAndDeleteFunc(key Key, old Value,
+	eq EqualFunc[Value]) (deleted bool) {
+
+	var n = t.findNode(key)
+	if n == t.sentinel || !eq(n.value, old) {
+		return
+	}
+	t.deleteNode(n)
+	return true
+}
+
+// Upsert sets value returned by given function for the key O(logn). The
+// function is called with current value of the key and true, or with zero
+// value and false, if the key doesn't exist. It returns true, if the key
+// is added. The Tree shouldn't be used inside the function.
+func (t *Tree[Key, Value]) Upsert(key Key,
+	fn func(old Value, exists bool) Value) (added bool) {
+
+	// the function is called before the descent changes sizes of the path,
+	// thus the Tree is not broken, if it panics
+	if n := t.findNode(key); n != t.sentinel {
+		t.setValue(n, fn(n.value, true))
+		return
+	}
+	var zero Value
+	var value = fn(zero, false)
+	var _, parent, c = t.descend(key, 1)
+	t.insertAt(parent, c, key, value)
+	return true
+}
+
+// GetOrSet returns existing value for the key O(logn). Otherwise, it sets
+// and returns given value. The loaded is true, if the value exists.
+func (t *Tree[Key, Value]) GetOrSet(key Key,
+	value Value) (actual Value, loaded bool) {
+
+	// a lookup doesn't copy nodes shared with clones
+	if n := t.findNode(key); n != t.sentinel {
+		return n.value, true
+	}
+	var _, parent, c = t.descend(key, 1)
+	t.insertAt(parent, c, key, value)
+	return value, false
+}
+
+// Swap sets the value for the key and returns the previous value O(logn).
+// The existed is false, if the key is added.
+func (t *Tree[Key, Value]) Swap(key Key,
+	value Value) (old Value, existed bool) {
+
+	var current, parent, c = t.descend(key, 1)
+	if current != t.sentinel {
+		t.resize(current.parent, -1)
+		old = current.value
+		t.setValue(current, value)
+		return old, true
+	}
+	t.insertAt(parent, c, key, value)
+	return
+}
+
+// LoadAndDelete deletes the key and returns its value O(logn). The loaded
+// is false, if the key doesn't exist.
+func (t *Tree[Key, Value]) LoadAndDelete(key Key) (value Value,
+	loaded bool) {
+
+	var n = t.findNode(key)
+	if n == t.sentinel {
+		return
+	}
+	value = n.value
+	t.deleteNode(n)
+	return value, true
+}
+
+// MoveEx is like the Move, but it also returns previous value of the
+// newKey, that is overwritten by the moved value. The overwritten is
+// false, if the newKey doesn't exist. Moving a key to itself changes
+// nothing O(2logn).
+func (t *Tree[Key, Value]) MoveEx(oldKey, newKey Key) (displaced Value,
+	overwritten, moved bool) {
+
+	var n = t.findNode(oldKey)
+	if n == t.sentinel {
+		return
+	}
+	if t.cmp(oldKey, newKey) == 0 {
+		return displaced, false, true
+	}
+	var value = n.value
+	t.deleteNode(n)
+	displaced, overwritten = t.Swap(newKey, value)
+	return displaced, overwritten, true
+}
@@ -0,0 +1,224 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree_CompareAndSwap(t *testing.T) {
+
+	var tr = New[int, string]()
+	tr.Set(1, "one")
+
+	assert.False(t, tr.CompareAndSwap(1, "two", "three"))
+	assert.False(t, tr.CompareAndSwap(2, "", "two"))
+	assert.False(t, tr.IsExist(2))
+	assert.True(t, tr.CompareAndSwap(1, "one", "ONE"))
+	assert.Equal(t, "ONE", tr.Get(1))
+
+	assert.False(t, tr.CompareAndDelete(1, "one"))
+	assert.False(t, tr.CompareAndDelete(2, ""))
+	assert.True(t, tr.CompareAndDelete(1, "ONE"))
+	assert.Equal(t, 0, tr.Len())
+
+	// not comparable
+	var tb = New[int, []byte]()
+	tb.Set(1, []byte("one"))
+	assert.Panics(t, func() { tb.CompareAndSwap(1, nil, nil) })
+	assert.True(t, tb.CompareAndSwapFunc(1, []byte("one"), []byte("two"),
+		bytes.Equal))
+	assert.Equal(t, []byte("two"), tb.Get(1))
+	assert.False(t, tb.CompareAndDeleteFunc(1, []byte("one"), bytes.Equal))
+	assert.True(t, tb.CompareAndDeleteFunc(1, []byte("two"), bytes.Equal))
+	checkTree(t, tb)
+}
+
+func TestTree_Upsert(t *testing.T) {
+
+	var tr = New[string, int]()
+	var inc = func(old int, exists bool) int {
+		if exists {
+			return old + 1
+		}
+		assert.Zero(t, old)
+		return 1
+	}
+
+	assert.True(t, tr.Upsert("a", inc))
+	assert.False(t, tr.Upsert("a", inc))
+	assert.True(t, tr.Upsert("b", inc))
+	assert.Equal(t, 2, tr.Get("a"))
+	assert.Equal(t, 1, tr.Get("b"))
+	checkTree(t, tr)
+
+	// panic
+	var fail = func(int, bool) int { panic("upsert") }
+	assert.Panics(t, func() { tr.Upsert("c", fail) })
+	assert.Panics(t, func() { tr.Upsert("a", fail) })
+	assert.Equal(t, 2, tr.Len())
+	assert.Equal(t, 2, tr.CountRange("", "z"))
+	assert.Equal(t, 2, tr.Rank("z"))
+	assert.Equal(t, 2, tr.Get("a"))
+	checkTree(t, tr)
+}
+
+func TestTree_GetOrSet(t *testing.T) {
+
+	var tr = New[int, string]()
+
+	var actual, loaded = tr.GetOrSet(1, "one")
+	assert.False(t, loaded)
+	assert.Equal(t, "one", actual)
+
+	actual, loaded = tr.GetOrSet(1, "ONE")
+	assert.True(t, loaded)
+	assert.Equal(t, "one", actual)
+	assert.Equal(t, 1, tr.Len())
+}
+
+func TestTree_swapNoChange(t *testing.T) {
+
+	var tr = New[int, string]()
+	for i := 0; i < 100; i++ {
+		tr.Set(i, "x")
+	}
+
+	// operations, that change nothing, don't copy shared nodes
+	var c = tr.Clone()
+	assert.False(t, c.CompareAndSwap(10, "y", "z"))
+	assert.False(t, c.CompareAndSwap(1000, "x", "z"))
+	assert.False(t, c.CompareAndDelete(10, "y"))
+	assert.False(t, c.CompareAndDelete(1000, "x"))
+	var actual, loaded = c.GetOrSet(10, "y")
+	assert.True(t, loaded)
+	assert.Equal(t, "x", actual)
+	var _, ok = c.LoadAndDelete(1000)
+	assert.False(t, ok)
+	var _, _, moved = c.MoveEx(1000, 1)
+	assert.False(t, moved)
+	assert.True(t, c.Move(10, 10))
+	assert.True(t, c.root == tr.root)
+	assert.Zero(t, copied(tr, c))
+
+	var empty = New[int, string]().Clone()
+	_, _, ok = empty.PopMin()
+	assert.False(t, ok)
+	_, _, ok = empty.PopMax()
+	assert.False(t, ok)
+
+	// a change copies the path only
+	assert.True(t, c.CompareAndSwap(10, "x", "y"))
+	assert.Equal(t, "x", tr.Get(10))
+	assert.Equal(t, "y", c.Get(10))
+	assert.Less(t, copied(tr, c), 20)
+	checkTree(t, c)
+	checkTree(t, tr)
+}
+
+func TestTree_swapRollback(t *testing.T) {
+
+	var tts = NewThreadSafe[int, int]()
+	tts.Set(1, 1)
+	tts.Set(2, 2)
+
+	var err = tts.Update(func(tx *Tree[int, int]) error {
+		tx.CompareAndSwap(1, 1, 10)
+		tx.CompareAndDelete(2, 2)
+		tx.Upsert(3, func(int, bool) int { return 3 })
+		tx.GetOrSet(4, 4)
+		return errors.New("rollback")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, []int{1, 2}, tts.Slice(0, 10))
+}
+
+func TestTree_Swap(t *testing.T) {
+
+	var tr = New[int, string]()
+
+	var old, existed = tr.Swap(1, "one")
+	assert.False(t, existed)
+	assert.Zero(t, old)
+
+	old, existed = tr.Swap(1, "ONE")
+	assert.True(t, existed)
+	assert.Equal(t, "one", old)
+	assert.Equal(t, "ONE", tr.Get(1))
+
+	var value, loaded = tr.LoadAndDelete(1)
+	assert.True(t, loaded)
+	assert.Equal(t, "ONE", value)
+	_, loaded = tr.LoadAndDelete(1)
+	assert.False(t, loaded)
+	assert.Equal(t, 0, tr.Len())
+}
+
+func TestTree_MoveEx(t *testing.T) {
+
+	var tr = New[int, string]()
+	for i, v := range []string{"zero", "one", "two"} {
+		tr.Set(i, v)
+	}
+
+	var displaced, overwritten, moved = tr.MoveEx(0, 10)
+	assert.True(t, moved)
+	assert.False(t, overwritten)
+	assert.Zero(t, displaced)
+
+	displaced, overwritten, moved = tr.MoveEx(10, 1)
+	assert.True(t, moved)
+	assert.True(t, overwritten)
+	assert.Equal(t, "one", displaced)
+	assert.Equal(t, []int{1, 2}, tr.SliceKeys(0, 100))
+	assert.Equal(t, []string{"zero", "two"}, tr.Slice(0, 100))
+
+	_, _, moved = tr.MoveEx(10, 1)
+	assert.False(t, moved)
+
+	// to itself
+	displaced, overwritten, moved = tr.MoveEx(2, 2)
+	assert.True(t, moved)
+	assert.False(t, overwritten)
+	assert.Equal(t, "two", tr.Get(2))
+	assert.True(t, tr.Move(2, 2))
+	assert.Equal(t, "two", tr.Get(2))
+	checkTree(t, tr)
+}
@@ -0,0 +1,91 @@
+package rbtree
+
+// Action is what WalkMut does with current key after its function
+// returns.
+type Action int
+
+// actions of the WalkMut
+const (
+	Keep   Action = iota // keep the key and continue
+	Delete               // delete the key and continue
+	Stop                 // keep the key and stop the walking
+)
+
+// WalkMutFunc is a mutable walker function type. It can change value of
+// the key through the pointer, the pointer is valid during the call only.
+type WalkMutFunc[Key, Value any] func(key Key, value *Value) (
+	action Action, err error)
+
+// WalkMut walks on the Tree like the Walk, but given function can change
+// values and delete keys. An error returned by the function stops the
+// walking and returned, the action is ignored in this case. The Tree
+// shouldn't be modified inside the function by other ways.
+//
+//	tr.WalkMut(from, to, func(key int, value *Item) (rbtree.Action, error) {
+//	    if value.Expired() {
+//	        return rbtree.Delete, nil
+//	    }
+//	    value.Hits = 0
+//	    return rbtree.Keep, nil
+//	})
+//
+// It takes O(logn + m + klogn), where m is number of walked keys and k is
+// number of deleted ones.
+func (t *Tree[Key, Value]) WalkMut(from, to Key,
+	walkFunc WalkMutFunc[Key, Value]) (err error) {
+
+	var (
+		ascending = t.cmp(from, to) <= 0
+		n         *node[Key, Value]
+		action    Action
+	)
+	if ascending {
+		n = t.ceilingNode(from)
+	} else {
+		n = t.floorNode(from)
+	}
+
+	for n != t.sentinel {
+		var c = t.cmp(n.key, to)
+		if ascending && c > 0 || !ascending && c < 0 {
+			return
+		}
+
+		n = t.own(n)
+		t.recordSet(n.key, n.value, true)
+		action, err = walkFunc(n.key, &n.value)
+		t.augmentPath(n) // the value can be changed
+		if err != nil {
+			return
+		}
+
+		switch action {
+		case Stop:
+			return
+		case Delete:
+			// deleteNode moves successor of a node with two children
+			// to the node, thus the node becomes the next one
+			if ascending && n.left != t.sentinel && n.right != t.sentinel {
+				t.deleteNode(n)
+				continue
+			}
+			var next = t.next(n, ascending)
+			t.deleteNode(n)
+			n = next
+		default:
+			n = t.next(n, ascending)
+		}
+	}
+
+	return
+}
+
+// next returns successor or predecessor of the n
+func (t *Tree[Key, Value]) next(n *node[Key, Value],
+	ascending bool) *node[Key, Value] {
+
+	if ascending {
+		return t.successor(n)
+	}
+	return t.predecessor(n)
+}
@@ -0,0 +1,173 @@
+//
+// Copyright (c) 2022 Konstantin Ivanov <kostyarin.ivanov@gmail.com>.
+// All rights reserved. This program is free software. It comes without
+// any warranty, to the extent permitted by applicable law. You can
+// redistribute it and/or modify it under the terms of the Unlicense.
+// See LICENSE file for more details or see below.
+//
+
+//
+// This is free and unencumbered software released into the public domain.
+//
+// Anyone is free to copy, modify, publish, use, compile, sell, or
+// distribute this software, either in source code form or as a compiled
+// binary, for any purpose, commercial or non-commercial, and by any
+// means.
+//
+// In jurisdictions that recognize copyright laws, the author or authors
+// of this software dedicate any and all copyright interest in the
+// software to the public domain. We make this dedication for the benefit
+// of the public at large and to the detriment of our heirs and
+// successors. We intend this dedication to be an overt act of
+// relinquishment in perpetuity of all present and future rights to this
+// software under copyright law.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND,
+// EXPRESS OR IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF
+// MERCHANTABILITY, FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT.
+// IN NO EVENT SHALL THE AUTHORS BE LIABLE FOR ANY CLAIM, DAMAGES OR
+// OTHER LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE,
+// ARISING FROM, OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR
+// OTHER DEALINGS IN THE SOFTWARE.
+//
+// For more information, please refer to <http://unlicense.org/>
+//
+
+package rbtree
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTree_WalkMut(t *testing.T) {
+
+	var tr = New[int, int]()
+	for i := 0; i < 10; i++ {
+		tr.Set(i, i)
+	}
+
+	var walked []int
+	var err = tr.WalkMut(2, 7, func(key int, value *int) (Action, error) {
+		walked = append(walked, key)
+		if key%2 == 0 {
+			return Delete, nil
+		}
+		*value *= 10
+		return Keep, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{2, 3, 4, 5, 6, 7}, walked)
+	assert.Equal(t, []int{0, 1, 3, 5, 7, 8, 9}, slices.Collect(tr.Keys()))
+	assert.Equal(t, []int{0, 1, 30, 50, 70, 8, 9}, slices.Collect(tr.Values()))
+	checkTree(t, tr)
+
+	// descending, stop
+	walked = walked[:0]
+	err = tr.WalkMut(100, 0, func(key int, value *int) (Action, error) {
+		walked = append(walked, key)
+		if key == 5 {
+			return Stop, nil
+		}
+		return Delete, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{9, 8, 7, 5}, walked)
+	assert.Equal(t, []int{0, 1, 3, 5}, slices.Collect(tr.Keys()))
+	checkTree(t, tr)
+
+	// error
+	var errWalk = errors.New("walk")
+	err = tr.WalkMut(0, 100, func(key int, value *int) (Action, error) {
+		return Delete, errWalk
+	})
+	assert.ErrorIs(t, err, errWalk)
+	assert.Equal(t, 4, tr.Len())
+
+	// empty
+	err = New[int, int]().WalkMut(0, 10, func(int, *int) (Action, error) {
+		t.Error("called")
+		return Keep, nil
+	})
+	assert.NoError(t, err)
+}
+
+func Test_randomWalkMut(t *testing.T) {
+
+	var rnd = rand.New(rand.NewSource(42))
+
+	for i := 0; i < 300; i++ {
+		var (
+			tr     = New[int, int]()
+			keys   []int
+			from   = rnd.Intn(600) - 50
+			to     = rnd.Intn(600) - 50
+			want   []int
+			walked []int
+		)
+		for j := rnd.Intn(200); j >= 0; j-- {
+			tr.Set(rnd.Intn(500), 0)
+		}
+		keys = slices.Collect(tr.Keys())
+		for _, key := range keys {
+			if key >= min(from, to) && key <= max(from, to) {
+				walked = append(walked, key)
+			}
+			if key < min(from, to) || key > max(from, to) || key%3 != 0 {
+				want = append(want, key)
+			}
+		}
+		if from > to {
+			slices.Reverse(walked)
+		}
+
+		var got []int
+		tr.WalkMut(from, to, func(key int, value *int) (Action, error) {
+			got = append(got, key)
+			if key%3 == 0 {
+				return Delete, nil
+			}
+			*value = key
+			return Keep, nil
+		})
+		if !assert.Equal(t, walked, got) ||
+			!assert.Equal(t, want, slices.Collect(tr.Keys())) {
+			return
+		}
+		for key, value := range tr.Range(from, to) {
+			assert.Equal(t, key, value)
+		}
+		checkTree(t, tr)
+	}
+}
+
+func TestTreeThreadSafe_WalkMut(t *testing.T) {
+
+	var tts = NewThreadSafe[int, int]()
+	for i := 0; i < 10; i++ {
+		tts.Set(i, i)
+	}
+
+	var err = tts.Update(func(tx *Tree[int, int]) error {
+		tx.WalkMut(0, 10, func(key int, value *int) (Action, error) {
+			if key < 5 {
+				*value = 100
+				return Keep, nil
+			}
+			return Delete, nil
+		})
+		return errors.New("rollback")
+	})
+	assert.Error(t, err)
+	assert.Equal(t, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}, tts.Slice(0, 10))
+
+	err = tts.WalkMut(0, 10, func(key int, value *int) (Action, error) {
+		return Delete, nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 0, tts.Len())
+}